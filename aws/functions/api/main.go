@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -30,7 +31,19 @@ func handler(ctx context.Context, req events.APIGatewayV2HTTPRequest) (*events.A
 		return returnError(err)
 	}
 
-	report := operator.Run(ctx, nil)
+	// The override only applies to this request. It is never read from the config so that it can
+	// not be left on for later scheduled runs.
+	namespaceDeleteOverride := false
+	if value, ok := req.QueryStringParameters["namespaceDeleteOverride"]; ok {
+		namespaceDeleteOverride, err = strconv.ParseBool(value)
+		if err != nil {
+			return returnError(fmt.Errorf("query parameter namespaceDeleteOverride is invalid: %w", err))
+		}
+	}
+
+	report := operator.Run(ctx, operator_types.NewRunInput().
+		SetFilter(filter).
+		SetNamespaceDeleteOverride(namespaceDeleteOverride))
 	err = report.Errors()
 
 	body, _ := json.Marshal(report)
@@ -11,15 +11,61 @@ import (
 
 	"github.com/aporeto-se/cloud-operator/aws/functions/types"
 	operator "github.com/aporeto-se/cloud-operator/aws/operator"
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
 )
 
 // NewClient returns new Client
 func NewClient(ctx context.Context) (*operator.Client, error) {
 
+	config, err := NewConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Build(ctx)
+}
+
+// Preflight returns the permission checks for the enabled ops
+func Preflight(ctx context.Context, filter *lib_types.Filter) (*lib_types.PreflightReport, error) {
+
+	config, err := NewConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return operator.Preflight(ctx, config, filter), nil
+}
+
+// Permissions returns the permissions required by the enabled ops in the config file and env
+func Permissions() (*permissions.Manifest, error) {
+
+	cloudOperatorConfig := types.NewCloudOperatorConfig()
+	err := lib_types.LoadConfigFile(cloudOperatorConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cloudOperatorConfig.SetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return operator.Permissions(&cloudOperatorConfig.CloudOperatorConfig), nil
+}
+
+// NewConfig returns new operator Config from the config file and env
+func NewConfig(ctx context.Context) (*operator.Config, error) {
+
 	// Logging has NOT been initialized yet
 
 	cloudOperatorConfig := types.NewCloudOperatorConfig()
-	err := cloudOperatorConfig.SetFromEnv()
+	err := lib_types.LoadConfigFile(cloudOperatorConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cloudOperatorConfig.SetFromEnv()
 	if err != nil {
 		return nil, err
 	}
@@ -91,15 +137,8 @@ func NewClient(ctx context.Context) (*operator.Client, error) {
 		return nil, err
 	}
 
-	operator, err := operator.NewConfig().
-		SetCloudOperatorConfig(&cloudOperatorConfig.CloudOperatorConfig).
-		SetPrismaClient(prismaClient).SetHTTPClient(httpClient).
-		Build(ctx)
-	if err != nil {
-		zap.L().Debug("returning NewOperator with error(s)")
-		return nil, err
-	}
-
 	zap.L().Debug("returning NewOperator")
-	return operator, nil
+	return operator.NewConfig().
+		SetCloudOperatorConfig(&cloudOperatorConfig.CloudOperatorConfig).
+		SetPrismaClient(prismaClient).SetHTTPClient(httpClient), nil
 }
@@ -8,6 +8,7 @@ import (
 
 	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
 	aws_sdk_ec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	aws_sdk_ec2_types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	aws_sdk_eks "github.com/aws/aws-sdk-go-v2/service/eks"
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
@@ -17,6 +18,12 @@ import (
 	"github.com/aporeto-se/cloud-operator/common/strbuilder"
 )
 
+const (
+	managedNodeClusterTag           = "eks:cluster-name"
+	selfManagedNodeClusterTagPrefix = "kubernetes.io/cluster/"
+	autoScalingGroupTag             = "aws:autoscaling:groupName"
+)
+
 // Cache this
 type Cache struct {
 	ec2 *aws_sdk_ec2.Client
@@ -43,6 +50,7 @@ func (t *Cache) init(ctx context.Context) error {
 	roleAccountMap := make(map[string]*RoleAccount)
 	clusterMap := make(map[string]*Cluster)
 	vpcMap := make(map[string]*Vpc)
+	subnetMap := make(map[string]*Subnet)
 
 	// Get AWS VPCs, Clusters and Instances
 
@@ -75,19 +83,23 @@ func (t *Cache) init(ctx context.Context) error {
 	// 2: Store VPC in map using key vpcID
 	// 3: Store VPC in slice
 
-	for _, awsVpc := range vpcList.Vpcs {
-		vpc := newVpc(&awsVpc)
+	// Index the slices rather than taking the address of the range variable; otherwise every
+	// entity would point at the last element
+
+	for i := range vpcList.Vpcs {
+		vpc := newVpc(&vpcList.Vpcs[i])
 		vpcMap[*vpc.VpcId] = vpc
 		t.Vpcs = append(t.Vpcs, vpc)
 	}
 
-	for _, awsSubnet := range awsSubnets.Subnets {
-		subnet := newSubnet(&awsSubnet)
+	for i := range awsSubnets.Subnets {
+		subnet := newSubnet(&awsSubnets.Subnets[i])
 		vpc := vpcMap[*subnet.VpcId]
 		if vpc == nil {
 			return fmt.Errorf("Missing VPC for vpcID %s", *subnet.VpcId)
 		}
 		vpc.Subnets = append(vpc.Subnets, subnet)
+		subnetMap[*subnet.SubnetId] = subnet
 	}
 
 	// Iterate AWS EKS Clusters and
@@ -139,6 +151,8 @@ func (t *Cache) init(ctx context.Context) error {
 				return err
 			}
 
+			cluster.Nodegroups = append(cluster.Nodegroups, nodeGroupName)
+
 			roleAccountName := arnToRole(*describeNodegroup.Nodegroup.NodeRole)
 			roleAccount := roleAccountMap[roleAccountName]
 			if roleAccount == nil {
@@ -147,10 +161,54 @@ func (t *Cache) init(ctx context.Context) error {
 
 			}
 
-			cluster.RoleAccounts = append(cluster.RoleAccounts, roleAccount)
-			roleAccount.Clusters = append(roleAccount.Clusters, cluster)
+			cluster.addRoleAccount(roleAccount)
 			roleAccountMap[roleAccountName] = roleAccount
 
+			for _, subnetID := range describeNodegroup.Nodegroup.Subnets {
+				subnet := subnetMap[subnetID]
+				if subnet == nil {
+					zap.L().Warn(fmt.Sprintf("nodegroup %s is missing its subnet %s", nodeGroupName, subnetID))
+					continue
+				}
+				cluster.addNodeSubnet(subnet)
+			}
+
+		}
+
+		// Fargate pods do not run the enforcer so the pod execution roles are not given
+		// Kubernetes auth policies. The profiles are recorded so the report can explain it.
+		fargateProfileNames, err := t.listFargateProfiles(ctx, *cluster.Name)
+		if err != nil {
+			zap.L().Debug("returning init with error(s)")
+			return err
+		}
+
+		for _, fargateProfileName := range fargateProfileNames {
+
+			describeFargateProfile, err := t.eks.DescribeFargateProfile(ctx, &aws_sdk_eks.DescribeFargateProfileInput{
+				ClusterName:        cluster.Name,
+				FargateProfileName: aws_sdk.String(fargateProfileName),
+			})
+			if err != nil {
+				zap.L().Debug("returning init with error(s)")
+				return err
+			}
+
+			fargateProfile := newFargateProfile(describeFargateProfile.FargateProfile)
+			fargateProfile.Cluster = cluster
+			cluster.FargateProfiles = append(cluster.FargateProfiles, fargateProfile)
+
+			zap.L().Debug(fmt.Sprintf("cluster %s has Fargate profile %s with pod execution role %s", name, fargateProfileName, fargateProfile.PodExecutionRole))
+
+			// Fargate pods are assigned addresses from the profile subnets
+			for _, subnetID := range fargateProfile.Subnets {
+				subnet := subnetMap[subnetID]
+				if subnet == nil {
+					zap.L().Warn(fmt.Sprintf("Fargate profile %s is missing its subnet %s", fargateProfileName, subnetID))
+					continue
+				}
+				cluster.addNodeSubnet(subnet)
+			}
 		}
 
 		clusterMap[name] = cluster
@@ -158,16 +216,34 @@ func (t *Cache) init(ctx context.Context) error {
 
 	}
 
+	// Self-managed nodes are not tagged eks:cluster-name. They are found by the
+	// kubernetes.io/cluster/<name> tag. Instances of an Auto Scaling group that does not
+	// propagate the tag take the cluster of the other instances in the group.
+
+	autoScalingGroupClusterMap := make(map[string]*Cluster)
+
+	for _, awsReservation := range awsInstances.Reservations {
+		for i := range awsReservation.Instances {
+			awsInstance := &awsReservation.Instances[i]
+			cluster, _ := instanceCluster(awsInstance.Tags, clusterMap)
+			autoScalingGroup := tagValue(awsInstance.Tags, autoScalingGroupTag)
+			if cluster != nil && autoScalingGroup != "" {
+				autoScalingGroupClusterMap[autoScalingGroup] = cluster
+			}
+		}
+	}
+
 	// Iterate AWS Instances and
 	// 1: Attach Instance to its VPC and its VPC to the Instance
 	// 2: Attach Instance to its Role Account
 	// 3: If Role Account does not exist, create Role Account and store in map using Role Account name as the key
 	// 4: Attach Instance to its Cluster and its Cluster to the Instance
+	// 5: If the Instance is a self-managed node attach its Role Account and subnet to the Cluster
 
 	for _, awsReservation := range awsInstances.Reservations {
-		for _, awsInstance := range awsReservation.Instances {
+		for i := range awsReservation.Instances {
 
-			instance := newInstance(&awsInstance)
+			instance := newInstance(&awsReservation.Instances[i])
 
 			vpc := vpcMap[*instance.VpcId]
 			if vpc == nil {
@@ -177,25 +253,24 @@ func (t *Cache) init(ctx context.Context) error {
 			instance.Vpc = vpc
 			vpc.Instances = append(vpc.Instances, instance)
 
-			// Map instance to its cluster by iterating its tags and looking for the key eks:cluster-name
-			// If present the value is the name of the cluster which we have in a map
-
-			var cluster *Cluster
-
-			for _, tag := range instance.Tags {
-				if *tag.Key == "eks:cluster-name" {
-					cluster = clusterMap[*tag.Value]
-					if cluster != nil {
-						instance.Cluster = cluster
-						cluster.Instances = append(cluster.Instances, instance)
-						zap.L().Debug(fmt.Sprintf("instanceID %s is part of cluster %s", *instance.InstanceId, *cluster.Name))
-					} else {
-						zap.L().Warn(fmt.Sprintf("instanceID %s is missing its cluster", *instance.InstanceId))
-					}
-					break
+			cluster, selfManaged := instanceCluster(instance.Tags, clusterMap)
+
+			if cluster == nil && tagValue(instance.Tags, managedNodeClusterTag) != "" {
+				zap.L().Warn(fmt.Sprintf("instanceID %s is missing its cluster", *instance.InstanceId))
+			} else if cluster == nil {
+				autoScalingGroup := tagValue(instance.Tags, autoScalingGroupTag)
+				if autoScalingGroup != "" {
+					cluster = autoScalingGroupClusterMap[autoScalingGroup]
+					selfManaged = cluster != nil
 				}
 			}
 
+			if cluster != nil {
+				instance.Cluster = cluster
+				cluster.Instances = append(cluster.Instances, instance)
+				zap.L().Debug(fmt.Sprintf("instanceID %s is part of cluster %s (self-managed=%t)", *instance.InstanceId, *cluster.Name, selfManaged))
+			}
+
 			if instance.IamInstanceProfile != nil {
 
 				roleAccountName := arnToRole(*instance.IamInstanceProfile.Arn)
@@ -214,6 +289,18 @@ func (t *Cache) init(ctx context.Context) error {
 					roleAccount.ComputeInstances = append(roleAccount.ComputeInstances, instance)
 				}
 
+				// Managed nodegroup roles were attached from DescribeNodegroup
+				if selfManaged {
+					cluster.addRoleAccount(roleAccount)
+				}
+
+			}
+
+			if selfManaged && instance.SubnetId != nil {
+				subnet := subnetMap[*instance.SubnetId]
+				if subnet != nil {
+					cluster.addNodeSubnet(subnet)
+				}
 			}
 
 			t.Instances = append(t.Instances, instance)
@@ -225,6 +312,31 @@ func (t *Cache) init(ctx context.Context) error {
 	return nil
 }
 
+// listFargateProfiles returns the names of the Fargate profiles of cluster
+func (t *Cache) listFargateProfiles(ctx context.Context, cluster string) ([]string, error) {
+
+	var result []string
+	var nextToken *string
+
+	for {
+
+		listFargateProfiles, err := t.eks.ListFargateProfiles(ctx, &aws_sdk_eks.ListFargateProfilesInput{
+			ClusterName: aws_sdk.String(cluster),
+			NextToken:   nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, listFargateProfiles.FargateProfileNames...)
+
+		nextToken = listFargateProfiles.NextToken
+		if nextToken == nil {
+			return result, nil
+		}
+	}
+}
+
 // KubeConfig returns Kubernetes Clientset for specified cluster
 func (t *Cache) KubeConfig(cluster *Cluster) (*kubernetes.Clientset, error) {
 
@@ -287,9 +399,10 @@ func (t *Cache) ReportString() string {
 	}
 
 	for _, x := range t.Clusters {
-		s.A(fmt.Sprintf("Cluster %s roles=[%s] vpc=\"%s\" instances=[%s]",
+		s.A(fmt.Sprintf("Cluster %s roles=[%s] vpc=\"%s\" instances=[%s] fargateProfiles=[%s]",
 			*x.Name, roleAccountsToCommaString(x.RoleAccounts),
-			*x.Vpc.VpcId, instanceIDsToCommaString(x.Instances)))
+			*x.Vpc.VpcId, instanceIDsToCommaString(x.Instances),
+			fargateProfilesToCommaString(x.FargateProfiles)))
 	}
 
 	for _, x := range t.RoleAccounts {
@@ -326,6 +439,18 @@ func instanceIDsToCommaString(input []*Instance) string {
 	return result
 }
 
+func fargateProfilesToCommaString(input []*FargateProfile) string {
+	result := ""
+	for _, x := range input {
+		if result == "" {
+			result = aws_sdk.ToString(x.FargateProfileName)
+		} else {
+			result = result + ", " + aws_sdk.ToString(x.FargateProfileName)
+		}
+	}
+	return result
+}
+
 func clusterToCommaString(input []*Cluster) string {
 	result := ""
 	for _, x := range input {
@@ -350,6 +475,37 @@ func clusterToCommaString(input []*Cluster) string {
 // 	return result
 // }
 
+// instanceCluster returns the cluster of an instance from its tags and true if the instance
+// is a self-managed node. Managed nodegroup instances are tagged eks:cluster-name while
+// self-managed nodes are tagged kubernetes.io/cluster/<name>.
+func instanceCluster(tags []aws_sdk_ec2_types.Tag, clusterMap map[string]*Cluster) (*Cluster, bool) {
+
+	name := tagValue(tags, managedNodeClusterTag)
+	if name != "" {
+		return clusterMap[name], false
+	}
+
+	for _, tag := range tags {
+		if tag.Key != nil && strings.HasPrefix(*tag.Key, selfManagedNodeClusterTagPrefix) {
+			cluster := clusterMap[strings.TrimPrefix(*tag.Key, selfManagedNodeClusterTagPrefix)]
+			if cluster != nil {
+				return cluster, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func tagValue(tags []aws_sdk_ec2_types.Tag, key string) string {
+	for _, tag := range tags {
+		if tag.Key != nil && *tag.Key == key && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
 func arnToRole(input string) string {
 	x := strings.Split(input, "/")
 	return x[len(x)-1]
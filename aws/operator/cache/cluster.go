@@ -1,15 +1,22 @@
 package cache
 
 import (
+	"fmt"
+	"strings"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
 	aws_sdk_eks_types "github.com/aws/aws-sdk-go-v2/service/eks/types"
 )
 
 // Cluster AWS Kubernetes Cluster
 type Cluster struct {
 	*aws_sdk_eks_types.Cluster
-	Vpc          *Vpc
-	Instances    []*Instance
-	RoleAccounts []*RoleAccount
+	Vpc             *Vpc
+	Instances       []*Instance
+	RoleAccounts    []*RoleAccount
+	NodeSubnets     []*Subnet
+	Nodegroups      []string
+	FargateProfiles []*FargateProfile
 }
 
 func newCluster(cluster *aws_sdk_eks_types.Cluster) *Cluster {
@@ -27,3 +34,146 @@ func (t *Cluster) ComputeInstancesLen() int {
 func (t *Cluster) RoleAccountLen() int {
 	return len(t.RoleAccounts)
 }
+
+// NodeCidrBlocks returns the CIDR blocks of the subnets used by the cluster nodegroups. If
+// the cluster has no nodegroups the CIDR blocks of the cluster subnets are returned.
+func (t *Cluster) NodeCidrBlocks() []string {
+
+	var result []string
+
+	for _, subnet := range t.NodeSubnets {
+		if subnet.CidrBlock != nil {
+			result = append(result, *subnet.CidrBlock)
+		}
+	}
+
+	if len(result) > 0 || t.Vpc == nil || t.ResourcesVpcConfig == nil {
+		return result
+	}
+
+	for _, subnetID := range t.ResourcesVpcConfig.SubnetIds {
+		for _, subnet := range t.Vpc.Subnets {
+			if subnet.SubnetId != nil && *subnet.SubnetId == subnetID && subnet.CidrBlock != nil {
+				result = append(result, *subnet.CidrBlock)
+			}
+		}
+	}
+
+	return result
+}
+
+// PodCidrBlocks returns the CIDR blocks pods are assigned from. The VPC CNI assigns pod
+// addresses from the subnets of the node network interfaces, so these are the CIDR blocks
+// of the nodegroup subnets and the subnets of every ENI attached to a cluster instance.
+// With custom networking the secondary ENIs are in the pod subnets.
+func (t *Cluster) PodCidrBlocks() []string {
+
+	if t.Vpc == nil {
+		return nil
+	}
+
+	subnetIDs := make(map[string]bool)
+
+	for _, subnet := range t.NodeSubnets {
+		if subnet.SubnetId != nil {
+			subnetIDs[*subnet.SubnetId] = true
+		}
+	}
+
+	for _, instance := range t.Instances {
+		for _, networkInterface := range instance.NetworkInterfaces {
+			if networkInterface.SubnetId != nil {
+				subnetIDs[*networkInterface.SubnetId] = true
+			}
+		}
+	}
+
+	var result []string
+
+	for _, subnet := range t.Vpc.Subnets {
+		if subnet.SubnetId != nil && subnetIDs[*subnet.SubnetId] && subnet.CidrBlock != nil {
+			result = append(result, *subnet.CidrBlock)
+		}
+	}
+
+	// Without nodes or nodegroups the cluster subnets are used
+	if len(result) == 0 {
+		return t.NodeCidrBlocks()
+	}
+
+	return result
+}
+
+// SubnetCidrBlocks returns the CIDR blocks of every subnet of the cluster VPC. These are
+// published as the cluster networks of KUBE_API_NET.
+func (t *Cluster) SubnetCidrBlocks() []string {
+
+	var result []string
+
+	if t.Vpc == nil {
+		return result
+	}
+
+	for _, subnet := range t.Vpc.Subnets {
+		if subnet.CidrBlock != nil {
+			result = append(result, *subnet.CidrBlock)
+		}
+	}
+
+	return result
+}
+
+// ServiceCidrBlocks returns the CIDR block services are assigned from
+func (t *Cluster) ServiceCidrBlocks() []string {
+
+	if t.KubernetesNetworkConfig == nil || t.KubernetesNetworkConfig.ServiceIpv4Cidr == nil {
+		return nil
+	}
+
+	return []string{*t.KubernetesNetworkConfig.ServiceIpv4Cidr}
+}
+
+// FargateOnly returns true if the cluster has Fargate profiles and no EC2 nodes. Fargate
+// does not run DaemonSets so the enforcer can not be installed on such a cluster.
+func (t *Cluster) FargateOnly() bool {
+	return len(t.FargateProfiles) > 0 && len(t.Nodegroups) == 0 && len(t.Instances) == 0
+}
+
+// Warnings returns the reasons workloads of the cluster are not protected by the enforcer
+func (t *Cluster) Warnings() []string {
+
+	if len(t.FargateProfiles) == 0 {
+		return nil
+	}
+
+	var names []string
+	for _, fargateProfile := range t.FargateProfiles {
+		names = append(names, aws_sdk.ToString(fargateProfile.FargateProfileName))
+	}
+
+	if t.FargateOnly() {
+		return []string{fmt.Sprintf("all workloads run on Fargate profiles [%s]; Fargate does not run DaemonSets so the enforcer is not installed", strings.Join(names, ", "))}
+	}
+
+	return []string{fmt.Sprintf("pods selected by Fargate profiles [%s] run on Fargate and are not protected by the enforcer DaemonSet", strings.Join(names, ", "))}
+}
+
+func (t *Cluster) addRoleAccount(roleAccount *RoleAccount) {
+	for _, v := range t.RoleAccounts {
+		if v == roleAccount {
+			return
+		}
+	}
+	t.RoleAccounts = append(t.RoleAccounts, roleAccount)
+	roleAccount.Clusters = append(roleAccount.Clusters, t)
+}
+
+func (t *Cluster) addNodeSubnet(subnet *Subnet) {
+	for _, v := range t.NodeSubnets {
+		if v == subnet {
+			return
+		}
+	}
+	t.NodeSubnets = append(t.NodeSubnets, subnet)
+	subnet.Clusters = append(subnet.Clusters, t)
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	aws_sdk_eks_types "github.com/aws/aws-sdk-go-v2/service/eks/types"
+)
+
+// FargateProfile AWS EKS Fargate Profile
+type FargateProfile struct {
+	*aws_sdk_eks_types.FargateProfile
+	PodExecutionRole string
+	Cluster          *Cluster
+}
+
+func newFargateProfile(fargateProfile *aws_sdk_eks_types.FargateProfile) *FargateProfile {
+
+	result := &FargateProfile{
+		FargateProfile: fargateProfile,
+	}
+
+	if fargateProfile.PodExecutionRoleArn != nil {
+		result.PodExecutionRole = arnToRole(*fargateProfile.PodExecutionRoleArn)
+	}
+
+	return result
+}
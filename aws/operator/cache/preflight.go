@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	aws_sdk_config "github.com/aws/aws-sdk-go-v2/config"
+	aws_sdk_ec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	aws_sdk_eks "github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/smithy-go"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const preflightTarget = "aws"
+
+// Preflight verifies the permissions used by Build and returns a check per call and enabled op.
+// EC2 calls are made with DryRun so only the permission is evaluated. Nothing is changed in the
+// account.
+func (t *Config) Preflight(ctx context.Context, ops []lib_types.Op) []*lib_types.PreflightCheck {
+
+	zap.L().Debug("entering Preflight")
+
+	if t.AWSRegion == "" {
+		zap.L().Debug("returning Preflight; attribute AWSRegion is required")
+		return []*lib_types.PreflightCheck{
+			lib_types.NewPreflightCheck("", preflightTarget, "configuration").SetError(fmt.Errorf("attribute AWSRegion is required")),
+		}
+	}
+
+	awsConfig, err := aws_sdk_config.LoadDefaultConfig(ctx)
+	if err != nil {
+		zap.L().Debug("returning Preflight; unable to load AWS config")
+		return []*lib_types.PreflightCheck{
+			lib_types.NewPreflightCheck("", preflightTarget, "credentials").SetError(err),
+		}
+	}
+
+	awsConfig.HTTPClient = t.GetHTTPClient()
+	awsConfig.Region = t.AWSRegion
+
+	ec2 := aws_sdk_ec2.NewFromConfig(awsConfig)
+	eks := aws_sdk_eks.NewFromConfig(awsConfig)
+
+	dryRun := aws_sdk.Bool(true)
+
+	// The cluster level calls are made against the first cluster, nodegroup and fargate
+	// profile found by the list calls. The table lists them after the list calls.
+	var cluster, nodegroup, fargateProfile *string
+
+	probes := map[string]preflightProbe{
+
+		"ec2:DescribeVpcs": func() (string, error) {
+			_, err := ec2.DescribeVpcs(ctx, &aws_sdk_ec2.DescribeVpcsInput{DryRun: dryRun})
+			return "", dryRunError(err)
+		},
+
+		"ec2:DescribeSubnets": func() (string, error) {
+			_, err := ec2.DescribeSubnets(ctx, &aws_sdk_ec2.DescribeSubnetsInput{DryRun: dryRun})
+			return "", dryRunError(err)
+		},
+
+		"ec2:DescribeInstances": func() (string, error) {
+			_, err := ec2.DescribeInstances(ctx, &aws_sdk_ec2.DescribeInstancesInput{DryRun: dryRun})
+			return "", dryRunError(err)
+		},
+
+		"eks:ListClusters": func() (string, error) {
+			out, err := eks.ListClusters(ctx, &aws_sdk_eks.ListClustersInput{})
+			if err == nil && len(out.Clusters) > 0 {
+				cluster = aws_sdk.String(out.Clusters[0])
+			}
+			return "", err
+		},
+
+		"eks:DescribeCluster": func() (string, error) {
+			if cluster == nil {
+				return "no cluster to check against", nil
+			}
+			_, err := eks.DescribeCluster(ctx, &aws_sdk_eks.DescribeClusterInput{Name: cluster})
+			return "", err
+		},
+
+		"eks:ListNodegroups": func() (string, error) {
+			if cluster == nil {
+				return "no cluster to check against", nil
+			}
+			out, err := eks.ListNodegroups(ctx, &aws_sdk_eks.ListNodegroupsInput{ClusterName: cluster})
+			if err == nil && len(out.Nodegroups) > 0 {
+				nodegroup = aws_sdk.String(out.Nodegroups[0])
+			}
+			return "", err
+		},
+
+		"eks:DescribeNodegroup": func() (string, error) {
+			if nodegroup == nil {
+				return "no nodegroup to check against", nil
+			}
+			_, err := eks.DescribeNodegroup(ctx, &aws_sdk_eks.DescribeNodegroupInput{ClusterName: cluster, NodegroupName: nodegroup})
+			return "", err
+		},
+
+		"eks:ListFargateProfiles": func() (string, error) {
+			if cluster == nil {
+				return "no cluster to check against", nil
+			}
+			out, err := eks.ListFargateProfiles(ctx, &aws_sdk_eks.ListFargateProfilesInput{ClusterName: cluster})
+			if err == nil && len(out.FargateProfileNames) > 0 {
+				fargateProfile = aws_sdk.String(out.FargateProfileNames[0])
+			}
+			return "", err
+		},
+
+		"eks:DescribeFargateProfile": func() (string, error) {
+			if fargateProfile == nil {
+				return "no fargate profile to check against", nil
+			}
+			_, err := eks.DescribeFargateProfile(ctx, &aws_sdk_eks.DescribeFargateProfileInput{ClusterName: cluster, FargateProfileName: fargateProfile})
+			return "", err
+		},
+	}
+
+	result := runPreflightProbes(permissions.Calls(permissions.ProviderAWS, ops), ops, probes)
+
+	zap.L().Debug("returning Preflight")
+	return result
+}
+
+// preflightProbe makes the call for one permission. It returns a reason if the call can not be made.
+type preflightProbe func() (string, error)
+
+// runPreflightProbes runs the probe of every call in order and returns its result for each
+// enabled op making the call. A call without a probe fails so the checks can not drift from the
+// permissions table.
+func runPreflightProbes(calls []*permissions.Call, ops []lib_types.Op, probes map[string]preflightProbe) []*lib_types.PreflightCheck {
+
+	var result []*lib_types.PreflightCheck
+
+	for _, call := range calls {
+
+		var skip string
+		var err error
+
+		probe, ok := probes[call.Permission]
+		if ok {
+			skip, err = probe()
+		} else {
+			err = fmt.Errorf("no preflight probe for %s", call.Permission)
+		}
+
+		for _, op := range call.EnabledOps(ops) {
+
+			check := lib_types.NewPreflightCheck(op, preflightTarget, call.Permission)
+
+			if skip != "" {
+				result = append(result, check.SetSkip(skip))
+				continue
+			}
+
+			result = append(result, check.SetError(err))
+		}
+	}
+
+	return result
+}
+
+// dryRunError returns nil if err reports that a DryRun request would have succeeded
+func dryRunError(err error) error {
+
+	var apiErr smithy.APIError
+
+	if errors.As(err, &apiErr) && apiErr.ErrorCode() == "DryRunOperation" {
+		return nil
+	}
+
+	if err == nil {
+		return fmt.Errorf("DryRun request was not evaluated")
+	}
+
+	return err
+}
@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestRunPreflightProbes(t *testing.T) {
+
+	calls := []*permissions.Call{
+		{Provider: permissions.ProviderAWS, Permission: "ec2:DescribeVpcs"},
+		{Provider: permissions.ProviderAWS, Permission: "eks:DescribeCluster"},
+		{Provider: permissions.ProviderAWS, Permission: "eks:ListNodegroups"},
+		{Provider: permissions.ProviderAWS, Ops: []lib_types.Op{lib_types.OpKubeEnforcer}, Permission: "eks:DescribeNodegroup"},
+	}
+
+	probes := map[string]preflightProbe{
+		"ec2:DescribeVpcs":      func() (string, error) { return "", nil },
+		"eks:DescribeCluster":   func() (string, error) { return "", fmt.Errorf("access denied") },
+		"eks:DescribeNodegroup": func() (string, error) { return "no nodegroup to check against", nil },
+	}
+
+	ops := []lib_types.Op{lib_types.OpComputeAuth, lib_types.OpKubeEnforcer}
+
+	want := []string{
+		"COMPUTE_AUTH ec2:DescribeVpcs PASS",
+		"KUBE_ENFORCER ec2:DescribeVpcs PASS",
+		"COMPUTE_AUTH eks:DescribeCluster FAIL",
+		"KUBE_ENFORCER eks:DescribeCluster FAIL",
+		"COMPUTE_AUTH eks:ListNodegroups FAIL",
+		"KUBE_ENFORCER eks:ListNodegroups FAIL",
+		"KUBE_ENFORCER eks:DescribeNodegroup SKIP",
+	}
+
+	got := runPreflightProbes(calls, ops, probes)
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d checks, want %d", len(got), len(want))
+	}
+
+	for i, check := range got {
+		if s := fmt.Sprintf("%s %s %s", check.Op, check.Check, check.Status); s != want[i] {
+			t.Fatalf("got %s, want %s", s, want[i])
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package operator
+
+import (
+	"reflect"
+	"testing"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	aws_sdk_ec2_types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/aporeto-se/cloud-operator/aws/operator/cache"
+	"github.com/aporeto-se/cloud-operator/aws/types"
+	"github.com/aporeto-se/cloud-operator/common/tag"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// roleAccount returns a role account with an instance in the state tagged team=name
+func roleAccount(name string, state aws_sdk_ec2_types.InstanceStateName) *cache.RoleAccount {
+	return &cache.RoleAccount{
+		Name: name,
+		ComputeInstances: []*cache.Instance{{
+			Instance: &aws_sdk_ec2_types.Instance{
+				InstanceId: aws_sdk.String("i-" + name),
+				State:      &aws_sdk_ec2_types.InstanceState{Name: state},
+				Tags:       []aws_sdk_ec2_types.Tag{{Key: aws_sdk.String("team"), Value: aws_sdk.String(name)}},
+			},
+		}},
+	}
+}
+
+func TestComputeEntitiesIgnoreRequestFilter(t *testing.T) {
+
+	tests := map[string]struct {
+		config  *lib_types.Filter
+		request *lib_types.Filter
+		want    []string
+	}{
+		"no filter": {
+			config: lib_types.NewFilter(),
+			want:   []string{"web", "api", "batch"},
+		},
+		"narrowing request filter deletes nothing": {
+			config:  lib_types.NewFilter(),
+			request: lib_types.NewFilter().AddComputeMatchTag("team", "web"),
+			want:    []string{"web", "api", "batch"},
+		},
+		"stopped instances are in scope": {
+			config: lib_types.NewFilter().AddComputeMatchStates("running"),
+			want:   []string{"web", "api", "batch"},
+		},
+		"config filter": {
+			config: lib_types.NewFilter().AddComputeMatchTag("team", "api"),
+			want:   []string{"api"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			client := &Client{
+				Cache: &cache.Cache{RoleAccounts: []*cache.RoleAccount{
+					roleAccount("web", aws_sdk_ec2_types.InstanceStateNameRunning),
+					roleAccount("api", aws_sdk_ec2_types.InstanceStateNameRunning),
+					roleAccount("batch", aws_sdk_ec2_types.InstanceStateNameStopped),
+				}},
+				cloudOperatorConfig: types.NewCloudOperatorConfig(),
+			}
+
+			tagMatcher, err := tag.NewMatcher(test.config, test.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, entity := range client.computeEntities(tagMatcher) {
+				got = append(got, entity.Name)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
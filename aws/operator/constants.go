@@ -1,10 +1,8 @@
 package operator
 
 const (
-	cloudProvider   = "Amazon Web Services"
-	dhcpImportLabel = "Cloud-Operator-DHCP"
-	authImportLabel = "Cloud-Operator-AUTH"
-	accountLabel    = "@auth:organization"
-	realm           = "@auth:realm=awssecuritytoken"
-	role            = "@auth:rolename"
+	cloudProvider    = "Amazon Web Services"
+	dhcpImportLabel  = "Cloud-Operator-DHCP"
+	authImportLabel  = "Cloud-Operator-AUTH"
+	infraImportLabel = "Cloud-Operator-INFRA"
 )
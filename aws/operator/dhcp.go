@@ -0,0 +1,244 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	aws_sdk_ec2_types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/c-robinson/iplib"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/aws/operator/cache"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+	dhcpNetworkPrefix = "dhcp-Server-"
+
+	// dhcpv6NetworkName is shared by every scope. DHCPv6 clients solicit the All DHCP Relay
+	// Agents and Servers multicast address and servers reply from their link local address.
+	dhcpv6NetworkName = "dhcpv6-Server"
+
+	dhcpAccountNetworkName = "AWS DHCP"
+)
+
+// operatingSystems a policy is created for each host operating system
+var operatingSystems = []struct {
+	name string
+	tag  string
+}{
+	{"Linux", "@os:host=linux"},
+	{"Windows", "@os:host=windows"},
+}
+
+// dhcpScope is a set of subnets that share an external network and policies
+type dhcpScope struct {
+	name    string
+	servers []string
+	subnets []string
+	ipv6    bool
+}
+
+// dhcpReport imports the DHCP external networks and policies grouped by the DHCPMode
+func (t *Client) dhcpReport(ctx context.Context) *lib_types.DHCPReport {
+
+	zap.L().Debug("entering dhcpReport")
+
+	report := lib_types.NewDHCPReport().
+		SetStatus(lib_types.OpStatusFailed)
+
+	scopes, err := t.dhcpScopes()
+	if err != nil {
+		zap.L().Debug("returning dhcpReport with error(s)")
+		return report.SetError(err)
+	}
+
+	prismaConfig := prisma_types.NewPrismaConfig(dhcpImportLabel)
+
+	ipv6 := false
+
+	for _, scope := range scopes {
+
+		var rules []*prisma_types.Rule
+
+		if len(scope.servers) > 0 {
+
+			prismaConfig.AddExternalnetwork(
+				prisma_types.NewExternalnetwork(scope.name).
+					SetDescription("auto-generated by Cloud Operator").
+					SetProtected(t.protectConfig).
+					SetPropagate(true).
+					AddEntry(scope.servers...))
+
+			rules = append(rules, prisma_types.NewRule().
+				SetTrafficActionAllow().
+				AddUDPProtocolPort(67).
+				AddUDPProtocolPort(68).
+				AddObject(
+					"@org:cloudaccount="+t.orgCloudAccount,
+					"@org:tenant="+t.orgTenant,
+					"externalnetwork:name="+scope.name))
+		}
+
+		if scope.ipv6 {
+
+			ipv6 = true
+
+			rules = append(rules, prisma_types.NewRule().
+				SetTrafficActionAllow().
+				AddUDPProtocolPort(546).
+				AddUDPProtocolPort(547).
+				AddObject(
+					"@org:cloudaccount="+t.orgCloudAccount,
+					"@org:tenant="+t.orgTenant,
+					"externalnetwork:name="+dhcpv6NetworkName))
+		}
+
+		if len(rules) == 0 {
+			continue
+		}
+
+		for _, operatingSystem := range operatingSystems {
+
+			policy := prisma_types.NewNetworkrulesetpolicy(scope.name + " " + operatingSystem.name).
+				SetDescription("auto-generated by Cloud Operator").
+				AddOutgoingRule(rules...).
+				SetProtected(t.protectConfig).
+				SetPropagate(true)
+
+			// Each subject is a separate clause so the policy applies to any of the subnets
+			if len(scope.subnets) == 0 {
+				policy.AddSubject(
+					"@org:cloudaccount="+t.orgCloudAccount,
+					"@org:tenant="+t.orgTenant,
+					operatingSystem.tag,
+				)
+			}
+
+			for _, subnetID := range scope.subnets {
+				policy.AddSubject(
+					"@org:cloudaccount="+t.orgCloudAccount,
+					"@org:tenant="+t.orgTenant,
+					"cloud:aws:subnet-id="+subnetID,
+					operatingSystem.tag,
+				)
+			}
+
+			prismaConfig.AddNetworkrulesetpolicy(policy)
+		}
+	}
+
+	if ipv6 {
+		prismaConfig.AddExternalnetwork(
+			prisma_types.NewExternalnetwork(dhcpv6NetworkName).
+				SetDescription("auto-generated by Cloud Operator").
+				SetProtected(t.protectConfig).
+				SetPropagate(true).
+				AddEntry("ff02::1:2", "fe80::/10"))
+	}
+
+	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", dhcpImportLabel))
+	err = t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
+
+	if err != nil {
+		zap.L().Debug("returning dhcpReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning dhcpReport")
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
+
+// dhcpScopes groups the subnets by the configured DHCPMode
+func (t *Client) dhcpScopes() ([]*dhcpScope, error) {
+
+	mode := t.cloudOperatorConfig.GetDHCPMode()
+
+	var result []*dhcpScope
+
+	account := &dhcpScope{name: dhcpAccountNetworkName}
+
+	for _, vpc := range t.Vpcs {
+
+		scope := &dhcpScope{name: dhcpNetworkPrefix + *vpc.VpcId}
+
+		for _, subnet := range vpc.Subnets {
+
+			subnetID := *subnet.SubnetId
+
+			server, err := dhcpServer(subnet)
+			if err != nil {
+				return nil, err
+			}
+
+			ipv6 := hasIPv6(subnet)
+
+			switch mode {
+
+			case lib_types.DHCPModeAccount:
+				account.servers = appendNonEmpty(account.servers, server)
+				account.ipv6 = account.ipv6 || ipv6
+
+			case lib_types.DHCPModeNetwork:
+				scope.servers = appendNonEmpty(scope.servers, server)
+				scope.subnets = append(scope.subnets, subnetID)
+				scope.ipv6 = scope.ipv6 || ipv6
+
+			default:
+				result = append(result, &dhcpScope{
+					name:    dhcpNetworkPrefix + subnetID,
+					servers: appendNonEmpty(nil, server),
+					subnets: []string{subnetID},
+					ipv6:    ipv6,
+				})
+
+			}
+		}
+
+		if mode == lib_types.DHCPModeNetwork && len(scope.subnets) > 0 {
+			result = append(result, scope)
+		}
+	}
+
+	if mode == lib_types.DHCPModeAccount {
+		result = append(result, account)
+	}
+
+	return result, nil
+}
+
+// dhcpServer returns the IPv4 DHCP server of subnet or an empty string for an IPv6 only subnet
+func dhcpServer(subnet *cache.Subnet) (string, error) {
+
+	if subnet.CidrBlock == nil {
+		return "", nil
+	}
+
+	_, ipna, err := iplib.ParseCIDR(*subnet.CidrBlock)
+	if err != nil {
+		return "", err
+	}
+
+	return ipna.FirstAddress().String(), nil
+}
+
+// hasIPv6 returns true if subnet has an associated IPv6 CIDR block
+func hasIPv6(subnet *cache.Subnet) bool {
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil &&
+			association.Ipv6CidrBlockState.State == aws_sdk_ec2_types.SubnetCidrBlockStateCodeAssociated {
+			return true
+		}
+	}
+	return false
+}
+
+func appendNonEmpty(list []string, v ...string) []string {
+	for _, s := range v {
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,150 @@
+package operator
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	aws_sdk "github.com/aws/aws-sdk-go-v2/aws"
+	aws_sdk_ec2_types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/aporeto-se/cloud-operator/aws/operator/cache"
+	"github.com/aporeto-se/cloud-operator/aws/types"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// dhcpSubnet returns a subnet with the IPv4 CIDR block (if not empty) and an associated IPv6
+// CIDR block if ipv6 is true
+func dhcpSubnet(id, cidrBlock string, ipv6 bool) *cache.Subnet {
+
+	subnet := &aws_sdk_ec2_types.Subnet{SubnetId: aws_sdk.String(id)}
+
+	if cidrBlock != "" {
+		subnet.CidrBlock = aws_sdk.String(cidrBlock)
+	}
+
+	if ipv6 {
+		subnet.Ipv6CidrBlockAssociationSet = []aws_sdk_ec2_types.SubnetIpv6CidrBlockAssociation{{
+			Ipv6CidrBlock:      aws_sdk.String("2600:1f18::/64"),
+			Ipv6CidrBlockState: &aws_sdk_ec2_types.SubnetCidrBlockState{State: aws_sdk_ec2_types.SubnetCidrBlockStateCodeAssociated},
+		}}
+	}
+
+	return &cache.Subnet{Subnet: subnet}
+}
+
+// dhcpClient returns a client with two VPCs. vpc-2 has an IPv6 only subnet.
+func dhcpClient(mode lib_types.DHCPMode) *Client {
+
+	vpc1 := &cache.Vpc{
+		Vpc: &aws_sdk_ec2_types.Vpc{VpcId: aws_sdk.String("vpc-1")},
+		Subnets: []*cache.Subnet{
+			dhcpSubnet("subnet-a", "10.0.1.0/24", false),
+			dhcpSubnet("subnet-b", "10.0.2.0/24", true),
+		},
+	}
+
+	vpc2 := &cache.Vpc{
+		Vpc:     &aws_sdk_ec2_types.Vpc{VpcId: aws_sdk.String("vpc-2")},
+		Subnets: []*cache.Subnet{dhcpSubnet("subnet-c", "", true)},
+	}
+
+	config := types.NewCloudOperatorConfig()
+	config.SetDHCPMode(mode)
+
+	return &Client{
+		Cache:               &cache.Cache{Vpcs: []*cache.Vpc{vpc1, vpc2}},
+		cloudOperatorConfig: config,
+	}
+}
+
+// scopeJSON returns the scopes as JSON for error messages
+func scopeJSON(scopes []*dhcpScope) string {
+	var result []map[string]interface{}
+	for _, scope := range scopes {
+		result = append(result, map[string]interface{}{
+			"name": scope.name, "servers": scope.servers, "subnets": scope.subnets, "ipv6": scope.ipv6,
+		})
+	}
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+func TestDHCPScopes(t *testing.T) {
+
+	tests := map[string]struct {
+		mode lib_types.DHCPMode
+		want []*dhcpScope
+	}{
+		"default is subnet": {
+			want: []*dhcpScope{
+				{name: "dhcp-Server-subnet-a", servers: []string{"10.0.1.1"}, subnets: []string{"subnet-a"}},
+				{name: "dhcp-Server-subnet-b", servers: []string{"10.0.2.1"}, subnets: []string{"subnet-b"}, ipv6: true},
+				{name: "dhcp-Server-subnet-c", subnets: []string{"subnet-c"}, ipv6: true},
+			},
+		},
+		"subnet": {
+			mode: lib_types.DHCPModeSubnet,
+			want: []*dhcpScope{
+				{name: "dhcp-Server-subnet-a", servers: []string{"10.0.1.1"}, subnets: []string{"subnet-a"}},
+				{name: "dhcp-Server-subnet-b", servers: []string{"10.0.2.1"}, subnets: []string{"subnet-b"}, ipv6: true},
+				{name: "dhcp-Server-subnet-c", subnets: []string{"subnet-c"}, ipv6: true},
+			},
+		},
+		"network": {
+			mode: lib_types.DHCPModeNetwork,
+			want: []*dhcpScope{
+				{name: "dhcp-Server-vpc-1", servers: []string{"10.0.1.1", "10.0.2.1"}, subnets: []string{"subnet-a", "subnet-b"}, ipv6: true},
+				{name: "dhcp-Server-vpc-2", subnets: []string{"subnet-c"}, ipv6: true},
+			},
+		},
+		"account": {
+			mode: lib_types.DHCPModeAccount,
+			want: []*dhcpScope{
+				{name: dhcpAccountNetworkName, servers: []string{"10.0.1.1", "10.0.2.1"}, ipv6: true},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			got, err := dhcpClient(test.mode).dhcpScopes()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %s, want %s", scopeJSON(got), scopeJSON(test.want))
+			}
+		})
+	}
+}
+
+func TestDHCPServer(t *testing.T) {
+
+	tests := map[string]struct {
+		subnet  *cache.Subnet
+		want    string
+		wantErr bool
+	}{
+		"ipv4":      {subnet: dhcpSubnet("subnet-a", "10.0.1.0/24", false), want: "10.0.1.1"},
+		"dual":      {subnet: dhcpSubnet("subnet-b", "10.0.2.0/24", true), want: "10.0.2.1"},
+		"ipv6 only": {subnet: dhcpSubnet("subnet-c", "", true)},
+		"invalid":   {subnet: dhcpSubnet("subnet-d", "10.0.1.0", false), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			got, err := dhcpServer(test.subnet)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
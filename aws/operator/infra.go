@@ -0,0 +1,135 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	"github.com/c-robinson/iplib"
+	"go.uber.org/zap"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// infraService is a cloud infrastructure service reached by enforced hosts
+type infraService struct {
+	name     string
+	entries  []string
+	tcpPorts []int
+	udpPorts []int
+}
+
+// infraReport imports an external network and outgoing rule for the instance metadata
+// service, the VPC DNS resolver and Amazon Time Sync. The IPv6 addresses are only reachable
+// from Nitro instances.
+func (t *Client) infraReport(ctx context.Context) *lib_types.InfraReport {
+
+	zap.L().Debug("entering infraReport")
+
+	report := lib_types.NewInfraReport().
+		SetStatus(lib_types.OpStatusFailed)
+
+	dnsServers, err := t.dnsServers()
+	if err != nil {
+		zap.L().Debug("returning infraReport with error(s)")
+		return report.SetError(err)
+	}
+
+	services := []*infraService{
+		{
+			name:     "AWS Metadata",
+			entries:  []string{"169.254.169.254", "fd00:ec2::254"},
+			tcpPorts: []int{80},
+		},
+		{
+			name:     "AWS DNS",
+			entries:  append(dnsServers, "169.254.169.253", "fd00:ec2::253"),
+			tcpPorts: []int{53},
+			udpPorts: []int{53},
+		},
+		{
+			name:     "AWS NTP",
+			entries:  []string{"169.254.169.123", "fd00:ec2::123"},
+			udpPorts: []int{123},
+		},
+	}
+
+	prismaConfig := prisma_types.NewPrismaConfig(infraImportLabel)
+
+	var rules []*prisma_types.Rule
+
+	for _, service := range services {
+
+		prismaConfig.AddExternalnetwork(
+			prisma_types.NewExternalnetwork(service.name).
+				SetDescription("auto-generated by Cloud Operator").
+				SetProtected(t.protectConfig).
+				SetPropagate(true).
+				AddEntry(service.entries...))
+
+		rule := prisma_types.NewRule().
+			SetTrafficActionAllow().
+			AddObject(
+				"@org:cloudaccount="+t.orgCloudAccount,
+				"@org:tenant="+t.orgTenant,
+				"externalnetwork:name="+service.name)
+
+		for _, port := range service.tcpPorts {
+			rule.AddTCPProtocolPort(port)
+		}
+
+		for _, port := range service.udpPorts {
+			rule.AddUDPProtocolPort(port)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	for _, operatingSystem := range operatingSystems {
+		prismaConfig.AddNetworkrulesetpolicy(
+			prisma_types.NewNetworkrulesetpolicy("AWS Infrastructure Services "+operatingSystem.name).
+				SetDescription("auto-generated by Cloud Operator").
+				AddOutgoingRule(rules...).
+				AddSubject(
+					"@org:cloudaccount="+t.orgCloudAccount,
+					"@org:tenant="+t.orgTenant,
+					operatingSystem.tag,
+				).
+				SetProtected(t.protectConfig).
+				SetPropagate(true))
+	}
+
+	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", infraImportLabel))
+	err = t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
+
+	if err != nil {
+		zap.L().Debug("returning infraReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning infraReport")
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
+
+// dnsServers returns the DNS resolver of each VPC. The resolver is at the base of the
+// primary VPC IPv4 CIDR block plus two.
+func (t *Client) dnsServers() ([]string, error) {
+
+	var result []string
+
+	for _, vpc := range t.Vpcs {
+
+		if vpc.CidrBlock == nil {
+			continue
+		}
+
+		_, ipna, err := iplib.ParseCIDR(*vpc.CidrBlock)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, iplib.IncrementIPBy(ipna.IP(), 2).String())
+	}
+
+	return result, nil
+}
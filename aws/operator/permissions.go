@@ -0,0 +1,15 @@
+package operator
+
+import (
+	"github.com/aporeto-se/cloud-operator/aws/types"
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+)
+
+// Permissions returns the least privilege cloud, Prisma and Kubernetes permissions required by the
+// enabled ops. No API is called.
+func Permissions(cloudOperatorConfig *types.CloudOperatorConfig) *permissions.Manifest {
+
+	namespace, _ := cloudOperatorConfig.GetNamespace()
+
+	return permissions.NewManifest(cloudProvider, permissions.ProviderAWS, namespace, cloudOperatorConfig.Ops)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/aporeto-se/cloud-operator/common/types"
 )
 
@@ -17,6 +19,9 @@ type CloudOperatorConfig struct {
 
 	// AWS Region
 	AWSRegion string
+
+	// DHCPMode controls how the DHCP external networks and policies are grouped
+	DHCPMode types.DHCPMode `json:"dhcpMode,omitempty" yaml:"dhcpMode,omitempty"`
 }
 
 // NewCloudOperatorConfig returns new intance of entity
@@ -37,7 +42,24 @@ func (t *CloudOperatorConfig) SetFromEnv() error {
 		t.AWSRegion = awsRegion
 	}
 
-	return t.CloudOperatorConfig.SetFromEnv()
+	var errors *multierror.Error
+
+	dhcpModeString := os.Getenv(types.DHCPModeEnv)
+	if dhcpModeString != "" {
+		dhcpMode, err := types.DHCPModeFromString(dhcpModeString)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			t.DHCPMode = dhcpMode
+		}
+	}
+
+	err := t.CloudOperatorConfig.SetFromEnv()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	return errors.ErrorOrNil()
 }
 
 // SetAWSRegion sets attribute and returns self
@@ -54,3 +76,17 @@ func (t *CloudOperatorConfig) GetAWSRegion() (string, error) {
 	}
 	return t.AWSRegion, err
 }
+
+// SetDHCPMode sets type and returns self
+func (t *CloudOperatorConfig) SetDHCPMode(v types.DHCPMode) *CloudOperatorConfig {
+	t.DHCPMode = v
+	return t
+}
+
+// GetDHCPMode returns type. If type is not set DHCPModeSubnet is returned.
+func (t *CloudOperatorConfig) GetDHCPMode() types.DHCPMode {
+	if t.DHCPMode == "" {
+		return types.DHCPModeSubnet
+	}
+	return t.DHCPMode
+}
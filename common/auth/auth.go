@@ -0,0 +1,190 @@
+package auth
+
+import (
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+	// enforcerRole is the role granted to compute and node identities
+	enforcerRole = "@auth:role=enforcer"
+
+	description = "auto-generated cloud operator policy"
+)
+
+// Claim is a single claim of an identity token
+type Claim struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// String returns the claim as a tag (@auth:key=value)
+func (t *Claim) String() string {
+	return "@auth:" + t.Key + "=" + t.Value
+}
+
+// Identity is the cloud identity enforcers authenticate with. Use AWSIdentity or GCPIdentity.
+type Identity struct {
+	Name   string   `json:"name" yaml:"name"`
+	Claims []*Claim `json:"claims" yaml:"claims"`
+}
+
+// AWSIdentity returns the identity of an IAM role in an AWS account
+func AWSIdentity(accountID, roleName string) *Identity {
+	return &Identity{
+		Name: roleName,
+		Claims: []*Claim{
+			{Key: "realm", Value: "awssecuritytoken"},
+			{Key: "organization", Value: accountID},
+			{Key: "rolename", Value: roleName},
+		},
+	}
+}
+
+// GCPIdentity returns the identity of a service account in a GCP project
+func GCPIdentity(projectNumber, email string) *Identity {
+	return &Identity{
+		Name: email,
+		Claims: []*Claim{
+			{Key: "realm", Value: "gcpidentitytoken"},
+			{Key: "projectnumber", Value: projectNumber},
+			{Key: "email", Value: email},
+		},
+	}
+}
+
+// Subject returns the claims as a policy subject
+func (t *Identity) Subject() []string {
+
+	var result []string
+
+	for _, claim := range t.Claims {
+		result = append(result, claim.String())
+	}
+
+	return result
+}
+
+// Policy is an API authorization policy granting a role to an identity
+type Policy struct {
+	Name                 string   `json:"name" yaml:"name"`
+	Description          string   `json:"description" yaml:"description"`
+	AuthorizedNamespace  string   `json:"authorizedNamespace" yaml:"authorizedNamespace"`
+	AuthorizedIdentities []string `json:"authorizedIdentities" yaml:"authorizedIdentities"`
+	Subject              []string `json:"subject" yaml:"subject"`
+	Protected            bool     `json:"protected" yaml:"protected"`
+}
+
+// Builder builds the API authorization policies of the COMPUTE_AUTH, KUBE_AUTH and
+// KUBE_WORKLOAD_AUTH ops
+type Builder struct {
+	computeAuth  bool
+	kubeAuth     bool
+	workloadAuth bool
+	protected    bool
+	workloadRole string
+	Policies     []*Policy
+}
+
+// NewBuilder returns a new Builder for ops. Identities are only added for enabled ops.
+func NewBuilder(ops []types.Op) *Builder {
+
+	builder := &Builder{workloadRole: types.DefaultKubeWorkloadAuthRole}
+
+	for _, op := range ops {
+
+		switch op {
+
+		case types.OpComputeAuth:
+			builder.computeAuth = true
+
+		case types.OpKubeAuth:
+			builder.kubeAuth = true
+
+		case types.OpKubeWorkloadAuth:
+			builder.workloadAuth = true
+
+		}
+	}
+
+	return builder
+}
+
+// SetProtected sets attribute and returns self
+func (t *Builder) SetProtected(protected bool) *Builder {
+	t.protected = protected
+	return t
+}
+
+// SetWorkloadRole sets the role granted by AddWorkload and returns self
+func (t *Builder) SetWorkloadRole(role string) *Builder {
+	t.workloadRole = role
+	return t
+}
+
+// AddCompute authorizes the compute instances of identity in namespace if COMPUTE_AUTH is
+// enabled and returns self
+func (t *Builder) AddCompute(identity *Identity, namespace string) *Builder {
+
+	if t.computeAuth {
+		t.Policies = append(t.Policies, t.policy("instances:"+identity.Name, identity, namespace, enforcerRole))
+	}
+
+	return t
+}
+
+// AddKube authorizes the nodes of cluster running as identity in namespace if KUBE_AUTH is
+// enabled and returns self
+func (t *Builder) AddKube(cluster string, identity *Identity, namespace string) *Builder {
+
+	if t.kubeAuth {
+		t.Policies = append(t.Policies, t.policy(cluster+":"+identity.Name, identity, namespace, enforcerRole))
+	}
+
+	return t
+}
+
+// AddWorkload authorizes the pods of cluster running as the Kubernetes serviceAccount
+// (namespace/name) mapped to identity in namespace with the workload role if
+// KUBE_WORKLOAD_AUTH is enabled and returns self
+func (t *Builder) AddWorkload(cluster, serviceAccount string, identity *Identity, namespace string) *Builder {
+
+	if t.workloadAuth {
+		t.Policies = append(t.Policies, t.policy(cluster+":"+serviceAccount, identity, namespace, t.workloadRole))
+	}
+
+	return t
+}
+
+// Build returns the Prisma config with the policies under importLabel
+func (t *Builder) Build(importLabel string) *prisma_types.PrismaConfig {
+	return t.AddTo(prisma_types.NewPrismaConfig(importLabel))
+}
+
+// AddTo adds the policies to prismaConfig and returns prismaConfig
+func (t *Builder) AddTo(prismaConfig *prisma_types.PrismaConfig) *prisma_types.PrismaConfig {
+
+	for _, policy := range t.Policies {
+		prismaConfig.AddApiauthorizationpolicy(
+			prisma_types.NewAPIAuthorizationPolicy(policy.Name).
+				SetDescription(policy.Description).
+				SetProtected(policy.Protected).
+				SetAuthorizedNamespace(policy.AuthorizedNamespace).
+				AddAuthorizedIdentity(policy.AuthorizedIdentities...).
+				AddSubject(policy.Subject...))
+	}
+
+	return prismaConfig
+}
+
+func (t *Builder) policy(name string, identity *Identity, namespace, role string) *Policy {
+	return &Policy{
+		Name:                 name,
+		Description:          description,
+		AuthorizedNamespace:  namespace,
+		AuthorizedIdentities: []string{role},
+		Subject:              identity.Subject(),
+		Protected:            t.protected,
+	}
+}
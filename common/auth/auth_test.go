@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// TestBuilder compares the Prisma config imported by Build with the golden files. Run with
+// -update to regenerate them after a change to the policies.
+func TestBuilder(t *testing.T) {
+
+	identities := map[string]struct {
+		compute  *Identity
+		kube     *Identity
+		workload *Identity
+	}{
+		"aws": {
+			compute:  AWSIdentity("123456789012", "compute-role"),
+			kube:     AWSIdentity("123456789012", "node-role"),
+			workload: AWSIdentity("123456789012", "workload-role"),
+		},
+		"gcp": {
+			compute:  GCPIdentity("123456789", "compute@project.iam.gserviceaccount.com"),
+			kube:     GCPIdentity("123456789", "node@project.iam.gserviceaccount.com"),
+			workload: GCPIdentity("123456789", "workload@project.iam.gserviceaccount.com"),
+		},
+	}
+
+	opsCombinations := map[string][]types.Op{
+		"none":         nil,
+		"compute":      {types.OpComputeAuth},
+		"kube":         {types.OpKubeAuth},
+		"compute_kube": {types.OpComputeAuth, types.OpKubeAuth},
+		"workload":     {types.OpKubeWorkloadAuth},
+		"all":          {types.OpComputeAuth, types.OpKubeAuth, types.OpKubeWorkloadAuth},
+	}
+
+	for provider, identity := range identities {
+		for opsName, ops := range opsCombinations {
+
+			name := provider + "_" + opsName
+
+			t.Run(name, func(t *testing.T) {
+
+				builder := NewBuilder(ops).
+					SetProtected(true).
+					AddCompute(identity.compute, "/tenant/cloudaccount/compute/"+identity.compute.Name).
+					AddKube("cluster", identity.kube, "/tenant/cloudaccount/kubernetes/cluster").
+					AddWorkload("cluster", "default/workload", identity.workload, "/tenant/cloudaccount/kubernetes/cluster")
+
+				got, err := json.MarshalIndent(builder.Build("cloud-operator"), "", "  ")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				golden := filepath.Join("testdata", name+".golden.json")
+
+				if *update {
+					err = os.WriteFile(golden, append(got, '\n'), 0644)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if string(append(got, '\n')) != string(want) {
+					t.Fatalf("prisma config does not match %s\ngot:\n%s\nwant:\n%s", golden, got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestWorkloadIdentity(t *testing.T) {
+
+	identity, err := AWSWorkloadIdentity(map[string]string{IRSAAnnotation: "arn:aws:iam::123456789012:role/path/workload-role"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(identity.Subject(), " "), "@auth:realm=awssecuritytoken @auth:organization=123456789012 @auth:rolename=workload-role"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	_, err = AWSWorkloadIdentity(map[string]string{IRSAAnnotation: "arn:aws:iam::123456789012:user/workload"})
+	if err == nil {
+		t.Fatalf("expected error for a user ARN")
+	}
+
+	identity, err = GCPWorkloadIdentity("project", "123456789")(map[string]string{GKEWorkloadIdentityAnnotation: "workload@project.iam.gserviceaccount.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := strings.Join(identity.Subject(), " "), "@auth:realm=gcpidentitytoken @auth:projectnumber=123456789 @auth:email=workload@project.iam.gserviceaccount.com"; got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	_, err = GCPWorkloadIdentity("project", "123456789")(map[string]string{GKEWorkloadIdentityAnnotation: "workload@other.iam.gserviceaccount.com"})
+	if err == nil {
+		t.Fatalf("expected error for a service account of another project")
+	}
+
+	identity, _ = GCPWorkloadIdentity("project", "123456789")(map[string]string{})
+	if identity != nil {
+		t.Fatalf("expected no identity without annotation")
+	}
+}
+
+func TestWorkloadRole(t *testing.T) {
+
+	identity := AWSIdentity("123456789012", "workload-role")
+	ops := []types.Op{types.OpKubeWorkloadAuth}
+
+	policies := NewBuilder(ops).AddWorkload("cluster", "default/workload", identity, "/tenant").Policies
+	if got := policies[0].AuthorizedIdentities; len(got) != 1 || got[0] != types.DefaultKubeWorkloadAuthRole {
+		t.Fatalf("got %v, want default role %s", got, types.DefaultKubeWorkloadAuthRole)
+	}
+
+	policies = NewBuilder(ops).SetWorkloadRole("@auth:role=custom").AddWorkload("cluster", "default/workload", identity, "/tenant").Policies
+	if got := policies[0].AuthorizedIdentities; len(got) != 1 || got[0] != "@auth:role=custom" {
+		t.Fatalf("got %v, want @auth:role=custom", got)
+	}
+}
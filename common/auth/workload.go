@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// IRSAAnnotation is the ServiceAccount annotation of an EKS IAM Role for Service Accounts
+	IRSAAnnotation = "eks.amazonaws.com/role-arn"
+
+	// GKEWorkloadIdentityAnnotation is the ServiceAccount annotation of a GKE Workload Identity
+	GKEWorkloadIdentityAnnotation = "iam.gke.io/gcp-service-account"
+
+	// gcpServiceAccountDomain is the email domain suffix of user managed service accounts
+	gcpServiceAccountDomain = ".iam.gserviceaccount.com"
+)
+
+// WorkloadIdentityFunc returns the cloud identity of a Kubernetes ServiceAccount from its
+// annotations. Nil is returned if the ServiceAccount is not annotated.
+type WorkloadIdentityFunc func(annotations map[string]string) (*Identity, error)
+
+// AWSWorkloadIdentity returns the identity of the IAM role in the IRSA annotation. The role
+// ARN is in the form arn:partition:iam::account:role/path/name.
+func AWSWorkloadIdentity(annotations map[string]string) (*Identity, error) {
+
+	arn := annotations[IRSAAnnotation]
+	if arn == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || parts[4] == "" || !strings.HasPrefix(parts[5], "role/") {
+		return nil, fmt.Errorf("annotation %s value %s is not an IAM role ARN", IRSAAnnotation, arn)
+	}
+
+	roleName := parts[5][strings.LastIndex(parts[5], "/")+1:]
+
+	return AWSIdentity(parts[4], roleName), nil
+}
+
+// GCPWorkloadIdentity returns a WorkloadIdentityFunc for the Workload Identity annotation of
+// a cluster in projectID. The identity token carries the number of the project owning the
+// service account, which is taken from the email (name@project.iam.gserviceaccount.com). Only
+// projectNumber is known so service accounts of other projects are rejected.
+func GCPWorkloadIdentity(projectID, projectNumber string) WorkloadIdentityFunc {
+	return func(annotations map[string]string) (*Identity, error) {
+
+		email := annotations[GKEWorkloadIdentityAnnotation]
+		if email == "" {
+			return nil, nil
+		}
+
+		at := strings.LastIndex(email, "@")
+		if at < 1 || !strings.HasSuffix(email, gcpServiceAccountDomain) {
+			return nil, fmt.Errorf("annotation %s value %s is not a service account email", GKEWorkloadIdentityAnnotation, email)
+		}
+
+		project := strings.TrimSuffix(email[at+1:], gcpServiceAccountDomain)
+		if project != projectID {
+			return nil, fmt.Errorf("service account %s is in project %s; only service accounts of project %s are supported", email, project, projectID)
+		}
+
+		return GCPIdentity(projectNumber, email), nil
+	}
+}
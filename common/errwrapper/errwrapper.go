@@ -82,7 +82,7 @@ func addConvenienceErr(err error) error {
 			zap.L().Debug("Adding Kubernetes convenience error")
 			var errors *multierror.Error
 			errors = multierror.Append(errors, err)
-			errors = multierror.Append(errors, fmt.Errorf("A Kubernetes authorization policy is required"))
+			errors = multierror.Append(errors, fmt.Errorf("A Kubernetes authorization policy is required. Run the preflight command to list the missing permissions"))
 			return errors.ErrorOrNil()
 		}
 	}
@@ -92,7 +92,7 @@ func addConvenienceErr(err error) error {
 			zap.L().Debug("Adding Prisma convenience error")
 			var errors *multierror.Error
 			errors = multierror.Append(errors, err)
-			errors = multierror.Append(errors, fmt.Errorf("A Prisma authorization policy is required"))
+			errors = multierror.Append(errors, fmt.Errorf("A Prisma authorization policy is required. Run the preflight command to list the missing permissions"))
 			return errors.ErrorOrNil()
 		}
 	}
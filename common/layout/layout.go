@@ -0,0 +1,249 @@
+package layout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+	computeSegment = "compute"
+	kubeSegment    = "kube"
+)
+
+// NamespaceLayout maps a cloud entity to the path of its namespace. The path is relative
+// to the cloud account namespace (/tenant/cloudaccount) and its segments are delineated
+// with a slash. Every segment except the last is an intermediate (layout) namespace.
+type NamespaceLayout interface {
+	Path(entity *types.NamespaceEntity) string
+
+	// LegacyPath returns the path the namespace of entity had before every name was
+	// sanitized or an empty string if the path is unchanged
+	LegacyPath(entity *types.NamespaceEntity) string
+}
+
+// NewNamespaceLayout returns the layout and naming configured in config or error
+func NewNamespaceLayout(config *types.CloudOperatorConfig) (NamespaceLayout, error) {
+
+	namer, err := newNamer(&config.NamespaceTemplates)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.GetNamespaceLayout() {
+
+	case types.NamespaceLayoutFlat:
+		return &namedLayout{namer: namer, place: flatPath}, nil
+
+	case types.NamespaceLayoutTyped:
+		return &namedLayout{namer: namer, place: typedPath}, nil
+
+	case types.NamespaceLayoutNetwork:
+		return &namedLayout{namer: namer, place: networkPath}, nil
+
+	}
+
+	return nil, fmt.Errorf("namespace layout %s is not supported", config.GetNamespaceLayout())
+}
+
+// namedLayout places the namespace named by namer with place
+type namedLayout struct {
+	namer *namer
+	place func(entity *types.NamespaceEntity, name string) string
+}
+
+func (t *namedLayout) Path(entity *types.NamespaceEntity) string {
+	return t.place(entity, t.namer.Name(entity))
+}
+
+func (t *namedLayout) LegacyPath(entity *types.NamespaceEntity) string {
+
+	name := t.namer.legacyName(entity)
+	if name == "" {
+		return ""
+	}
+
+	return t.place(entity, name)
+}
+
+// flatPath places every namespace directly below the cloud account: <name>
+func flatPath(entity *types.NamespaceEntity, name string) string {
+	return join(name)
+}
+
+// typedPath groups namespaces by entity type: compute/<name> and kube/<name>
+func typedPath(entity *types.NamespaceEntity, name string) string {
+
+	if entity.Type == types.CloudEntityTypeKubernetes {
+		return join(kubeSegment, name)
+	}
+
+	return join(computeSegment, name)
+}
+
+// networkPath groups namespaces by region and network: <region>/<network>/<name> for
+// clusters and <region>/<name> for compute as role and service accounts are not bound to
+// a single network
+func networkPath(entity *types.NamespaceEntity, name string) string {
+
+	if entity.Type == types.CloudEntityTypeKubernetes {
+		return join(Sanitize(entity.Region), Sanitize(entity.Network), name)
+	}
+
+	return join(Sanitize(entity.Region), name)
+}
+
+// Collisions returns the entities that conflict keyed by path. Entities conflict when they
+// map to the same path or when the path of one is an intermediate namespace of the path of
+// another. Identical entities are not a conflict.
+func Collisions(layout NamespaceLayout, entities ...*types.NamespaceEntity) map[string][]*types.NamespaceEntity {
+
+	paths := make(map[string][]*types.NamespaceEntity)
+
+	for _, entity := range entities {
+
+		path := layout.Path(entity)
+
+		duplicate := false
+		for _, other := range paths[path] {
+			if other.Equal(entity) {
+				duplicate = true
+				break
+			}
+		}
+
+		if !duplicate {
+			paths[path] = append(paths[path], entity)
+		}
+	}
+
+	result := make(map[string][]*types.NamespaceEntity)
+
+	for path, entities := range paths {
+
+		conflicts := append([]*types.NamespaceEntity{}, entities...)
+
+		for other, otherEntities := range paths {
+			if strings.HasPrefix(other, path+"/") {
+				conflicts = append(conflicts, otherEntities...)
+			}
+		}
+
+		if len(conflicts) > 1 {
+			result[path] = conflicts
+		}
+	}
+
+	return result
+}
+
+// join joins the non empty segments with a slash
+func join(segments ...string) string {
+
+	var result []string
+
+	for _, segment := range segments {
+		if segment != "" {
+			result = append(result, segment)
+		}
+	}
+
+	return strings.Join(result, "/")
+}
+
+// Segments returns the segments of path
+func Segments(path string) []string {
+
+	var result []string
+
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			result = append(result, segment)
+		}
+	}
+
+	return result
+}
+
+// NewClient returns a Prisma client for the namespace at path relative to the namespace of
+// parent. Each segment of the path must exist.
+func NewClient(ctx context.Context, parent *prisma_api.Client, path string) (*prisma_api.Client, error) {
+
+	zap.L().Debug("entering NewClient")
+
+	client := parent
+
+	for _, segment := range Segments(path) {
+
+		var err error
+
+		client, err = client.NewClient(ctx, segment)
+		if err != nil {
+			zap.L().Debug("returning NewClient with error(s)")
+			return nil, err
+		}
+	}
+
+	zap.L().Debug("returning NewClient")
+	return client, nil
+}
+
+// Exists returns true if the namespace at path relative to the namespace of parent exists
+func Exists(ctx context.Context, parent *prisma_api.Client, path string) (bool, error) {
+
+	segments := Segments(path)
+	if len(segments) == 0 {
+		return true, nil
+	}
+
+	client := parent
+
+	for i, segment := range segments {
+
+		if !client.HasNamespace(segment) {
+			return false, nil
+		}
+
+		if i == len(segments)-1 {
+			break
+		}
+
+		var err error
+
+		client, err = client.NewClient(ctx, segment)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Conflicts returns an error keyed by the path of each entity that conflicts with another
+// entity. Anything written to one of these paths would be shared between the entities.
+func Conflicts(layout NamespaceLayout, entities ...*types.NamespaceEntity) map[string]error {
+
+	result := make(map[string]error)
+
+	for path, conflicting := range Collisions(layout, entities...) {
+
+		var names []string
+		for _, entity := range conflicting {
+			names = append(names, fmt.Sprintf("%s %s", entity.Type, entity.Name))
+		}
+
+		err := fmt.Errorf("namespace %s conflicts between %s", path, strings.Join(names, ", "))
+		zap.L().Warn(err.Error())
+
+		for _, entity := range conflicting {
+			result[layout.Path(entity)] = err
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,122 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestLayoutPath(t *testing.T) {
+
+	compute := types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role1").
+		SetRegion("us-east-1")
+
+	kube := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster1").
+		SetRegion("us-east-1").
+		SetNetwork("vpc-1234")
+
+	kubeNoNetwork := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster2").
+		SetRegion("us-central1")
+
+	kubeNetworkURL := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster3").
+		SetRegion("us-central1").
+		SetNetwork("projects/p1/global/networks/default")
+
+	tests := map[string]struct {
+		layout types.NamespaceLayoutType
+		entity *types.NamespaceEntity
+		want   string
+	}{
+		"flat compute":              {layout: types.NamespaceLayoutFlat, entity: compute, want: "role1"},
+		"flat kube":                 {layout: types.NamespaceLayoutFlat, entity: kube, want: "cluster1"},
+		"typed compute":             {layout: types.NamespaceLayoutTyped, entity: compute, want: "compute/role1"},
+		"typed kube":                {layout: types.NamespaceLayoutTyped, entity: kube, want: "kube/cluster1"},
+		"network compute":           {layout: types.NamespaceLayoutNetwork, entity: compute, want: "us-east-1/role1"},
+		"network kube":              {layout: types.NamespaceLayoutNetwork, entity: kube, want: "us-east-1/vpc-1234/cluster1"},
+		"network kube no network":   {layout: types.NamespaceLayoutNetwork, entity: kubeNoNetwork, want: "us-central1/cluster2"},
+		"network kube network URL":  {layout: types.NamespaceLayoutNetwork, entity: kubeNetworkURL, want: "us-central1/projects-p1-global-networks-default/cluster3"},
+		"network compute no region": {layout: types.NamespaceLayoutNetwork, entity: types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role2"), want: "role2"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			config := &types.CloudOperatorConfig{}
+			config.SetNamespaceLayout(test.layout)
+
+			layout, err := NewNamespaceLayout(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := layout.Path(test.entity); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLayoutPathTemplate(t *testing.T) {
+
+	config := &types.CloudOperatorConfig{}
+	config.SetNamespaceLayout(types.NamespaceLayoutTyped)
+	config.NamespaceTemplates.Kube = `{{ .Region }}-{{ trimPrefix .Name "eks-" }}`
+
+	layout, err := NewNamespaceLayout(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kube := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "eks-prod").SetRegion("us-east-1")
+	if got := layout.Path(kube); got != "kube/us-east-1-prod" {
+		t.Fatalf("got %s, want kube/us-east-1-prod", got)
+	}
+
+	// The compute template is not set
+	compute := types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role1").SetRegion("us-east-1")
+	if got := layout.Path(compute); got != "compute/role1" {
+		t.Fatalf("got %s, want compute/role1", got)
+	}
+}
+
+func TestLayoutInvalid(t *testing.T) {
+
+	config := &types.CloudOperatorConfig{}
+	config.SetNamespaceLayout("TREE")
+
+	if _, err := NewNamespaceLayout(config); err == nil {
+		t.Fatalf("expected error for an unsupported layout")
+	}
+
+	config = &types.CloudOperatorConfig{}
+	config.NamespaceTemplates.Compute = "{{ .Name"
+
+	if _, err := NewNamespaceLayout(config); err == nil {
+		t.Fatalf("expected error for an invalid template")
+	}
+}
+
+func TestCollisions(t *testing.T) {
+
+	config := &types.CloudOperatorConfig{}
+	config.SetNamespaceLayout(types.NamespaceLayoutNetwork)
+
+	layout, err := NewNamespaceLayout(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cluster := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster1").SetRegion("us-east-1").SetNetwork("vpc-1")
+
+	// A role named like the network of a cluster in the same region owns the cluster's
+	// intermediate namespace
+	role := types.NewNamespaceEntity(types.CloudEntityTypeCompute, "vpc-1").SetRegion("us-east-1")
+
+	other := types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster1").SetRegion("us-west-2").SetNetwork("vpc-1")
+
+	collisions := Collisions(layout, cluster, cluster, role, other)
+
+	if len(collisions) != 1 || len(collisions["us-east-1/vpc-1"]) != 2 {
+		t.Fatalf("expected one collision of us-east-1/vpc-1 with two entities, got %v", collisions)
+	}
+}
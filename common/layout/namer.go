@@ -0,0 +1,138 @@
+package layout
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+	// maxNameLength is the maximum length of a Prisma namespace name
+	maxNameLength = 64
+)
+
+var templateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    strings.ReplaceAll,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+}
+
+// namer names entities with the configured templates
+type namer struct {
+	compute *template.Template
+	kube    *template.Template
+}
+
+func newNamer(templates *types.NamespaceTemplates) (*namer, error) {
+
+	compute, err := parseTemplate("compute", templates.Compute)
+	if err != nil {
+		return nil, err
+	}
+
+	kube, err := parseTemplate("kube", templates.Kube)
+	if err != nil {
+		return nil, err
+	}
+
+	return &namer{
+		compute: compute,
+		kube:    kube,
+	}, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s template is invalid: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// template returns the template of the type of entity or nil if it is not set
+func (t *namer) template(entity *types.NamespaceEntity) *template.Template {
+
+	if entity.Type == types.CloudEntityTypeKubernetes {
+		return t.kube
+	}
+
+	return t.compute
+}
+
+// Name returns the namespace name for entity. Every name is sanitized and truncated to the
+// maximum length. If the template fails to execute or returns an empty name the entity name
+// is used.
+func (t *namer) Name(entity *types.NamespaceEntity) string {
+
+	tmpl := t.template(entity)
+	if tmpl == nil {
+		return Sanitize(entity.Name)
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, entity)
+	if err != nil {
+		zap.L().Warn(fmt.Sprintf("namespace template failed for %s %s; using name: %s", entity.Type, entity.Name, err))
+		return Sanitize(entity.Name)
+	}
+
+	name := Sanitize(buf.String())
+	if name == "" {
+		zap.L().Warn(fmt.Sprintf("namespace template returned an empty name for %s %s; using name", entity.Type, entity.Name))
+		return Sanitize(entity.Name)
+	}
+
+	return name
+}
+
+// legacyName returns the name entity had before every name was sanitized or an empty string
+// if the name is unchanged. Only names not produced by a template were used as is.
+func (t *namer) legacyName(entity *types.NamespaceEntity) string {
+
+	if t.template(entity) != nil {
+		return ""
+	}
+
+	if entity.Name == t.Name(entity) {
+		return ""
+	}
+
+	return entity.Name
+}
+
+// Sanitize returns v with every character Prisma does not allow in a namespace name
+// replaced with a dash and truncated to the maximum length
+func Sanitize(v string) string {
+
+	result := []byte(strings.TrimSpace(v))
+
+	for i, c := range result {
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+		case c == '-' || c == '_':
+		default:
+			result[i] = '-'
+		}
+	}
+
+	if len(result) > maxNameLength {
+		result = result[:maxNameLength]
+	}
+
+	return string(result)
+}
@@ -0,0 +1,170 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestSanitize(t *testing.T) {
+
+	long := strings.Repeat("a", maxNameLength)
+
+	tests := map[string]struct {
+		name string
+		want string
+	}{
+		"empty":                   {name: "", want: ""},
+		"allowed":                 {name: "eks-Prod_1", want: "eks-Prod_1"},
+		"email":                   {name: "sa@project.iam.gserviceaccount.com", want: "sa-project-iam-gserviceaccount-com"},
+		"slash":                   {name: "projects/p1/networks/default", want: "projects-p1-networks-default"},
+		"spaces trimmed":          {name: "  role 1 ", want: "role-1"},
+		"non ascii":               {name: "rôle", want: "r--le"},
+		"maximum length":          {name: long, want: long},
+		"truncated":               {name: long + "b", want: long},
+		"truncated after replace": {name: "a.b" + long, want: "a-b" + long[:maxNameLength-3]},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := Sanitize(test.name); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNamerName(t *testing.T) {
+
+	long := strings.Repeat("a", maxNameLength+10)
+
+	tests := map[string]struct {
+		templates  types.NamespaceTemplates
+		entity     *types.NamespaceEntity
+		want       string
+		wantLegacy string
+	}{
+		"plain": {
+			entity: types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role1"),
+			want:   "role1",
+		},
+		"untemplated name is sanitized": {
+			entity:     types.NewNamespaceEntity(types.CloudEntityTypeCompute, "sa@p1.iam.gserviceaccount.com"),
+			want:       "sa-p1-iam-gserviceaccount-com",
+			wantLegacy: "sa@p1.iam.gserviceaccount.com",
+		},
+		"untemplated name is truncated": {
+			entity:     types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, long),
+			want:       long[:maxNameLength],
+			wantLegacy: long,
+		},
+		"template": {
+			templates: types.NamespaceTemplates{Kube: "{{ .Region }}.{{ .Name }}"},
+			entity:    types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, "cluster1").SetRegion("us-east-1"),
+			want:      "us-east-1-cluster1",
+		},
+		"template of other type": {
+			templates:  types.NamespaceTemplates{Kube: "{{ .Region }}-{{ .Name }}"},
+			entity:     types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role.1").SetRegion("us-east-1"),
+			want:       "role-1",
+			wantLegacy: "role.1",
+		},
+		"template fails": {
+			templates: types.NamespaceTemplates{Compute: "{{ index .Tags \"team\" 0 }}"},
+			entity:    types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role.1"),
+			want:      "role-1",
+		},
+		"template empty": {
+			templates: types.NamespaceTemplates{Compute: "{{ trimPrefix .Name \"role.1\" }}"},
+			entity:    types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role.1"),
+			want:      "role-1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			namer, err := newNamer(&test.templates)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := namer.Name(test.entity); got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+
+			if got := namer.legacyName(test.entity); got != test.wantLegacy {
+				t.Fatalf("got legacy name %s, want %s", got, test.wantLegacy)
+			}
+		})
+	}
+}
+
+func TestLegacyPath(t *testing.T) {
+
+	config := &types.CloudOperatorConfig{}
+	config.SetNamespaceLayout(types.NamespaceLayoutTyped)
+
+	layout, err := NewNamespaceLayout(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entity := types.NewNamespaceEntity(types.CloudEntityTypeCompute, "sa@p1.iam.gserviceaccount.com")
+
+	if got := layout.Path(entity); got != "compute/sa-p1-iam-gserviceaccount-com" {
+		t.Fatalf("got %s, want compute/sa-p1-iam-gserviceaccount-com", got)
+	}
+
+	if got := layout.LegacyPath(entity); got != "compute/sa@p1.iam.gserviceaccount.com" {
+		t.Fatalf("got %s, want compute/sa@p1.iam.gserviceaccount.com", got)
+	}
+
+	if got := layout.LegacyPath(types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role1")); got != "" {
+		t.Fatalf("got %s, want no legacy path", got)
+	}
+}
+
+func TestSanitizeCollisions(t *testing.T) {
+
+	config := &types.CloudOperatorConfig{}
+
+	layout, err := NewNamespaceLayout(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefix := strings.Repeat("a", maxNameLength)
+
+	tests := map[string]struct {
+		entities []*types.NamespaceEntity
+		want     string
+	}{
+		"sanitized": {
+			entities: []*types.NamespaceEntity{
+				types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role.1"),
+				types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role@1"),
+			},
+			want: "role-1",
+		},
+		"truncated": {
+			entities: []*types.NamespaceEntity{
+				types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, prefix+"-prod"),
+				types.NewNamespaceEntity(types.CloudEntityTypeKubernetes, prefix+"-dev"),
+			},
+			want: prefix,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			conflicts := Conflicts(layout, test.entities...)
+
+			if len(conflicts) != 1 || conflicts[test.want] == nil {
+				t.Fatalf("expected a conflict at %s, got %v", test.want, conflicts)
+			}
+		})
+	}
+}
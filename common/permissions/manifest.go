@@ -0,0 +1,221 @@
+package permissions
+
+import (
+	"strings"
+
+	k8srbacv1 "k8s.io/api/rbac/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// manifestName is the name of the generated policies and roles
+const manifestName = "cloud-operator"
+
+// Manifest is the least privilege policy for each API the operator calls with the enabled ops
+type Manifest struct {
+	CloudProvider string                 `json:"cloudProvider" yaml:"cloudProvider"`
+	Ops           []types.Op             `json:"ops" yaml:"ops"`
+	AWS           *AWSPolicy             `json:"aws,omitempty" yaml:"aws,omitempty"`
+	GCP           *GCPRole               `json:"gcp,omitempty" yaml:"gcp,omitempty"`
+	Prisma        *PrismaPolicy          `json:"prisma" yaml:"prisma"`
+	Kubernetes    *k8srbacv1.ClusterRole `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+	Calls         []*Call                `json:"calls" yaml:"calls"`
+}
+
+// AWSPolicy AWS IAM policy document
+type AWSPolicy struct {
+	Version   string          `json:"Version" yaml:"Version"`
+	Statement []*AWSStatement `json:"Statement" yaml:"Statement"`
+}
+
+// AWSStatement AWS IAM policy statement
+type AWSStatement struct {
+	Sid      string   `json:"Sid" yaml:"Sid"`
+	Effect   string   `json:"Effect" yaml:"Effect"`
+	Action   []string `json:"Action" yaml:"Action"`
+	Resource string   `json:"Resource" yaml:"Resource"`
+}
+
+// GCPRole GCP custom role definition
+type GCPRole struct {
+	Title               string   `json:"title" yaml:"title"`
+	Description         string   `json:"description" yaml:"description"`
+	Stage               string   `json:"stage" yaml:"stage"`
+	IncludedPermissions []string `json:"includedPermissions" yaml:"includedPermissions"`
+}
+
+// PrismaPolicy Prisma API authorization policy. The subject is the identity of the
+// operator and must be set when the policy is created.
+type PrismaPolicy struct {
+	Name                 string   `json:"name" yaml:"name"`
+	Description          string   `json:"description" yaml:"description"`
+	AuthorizedNamespace  string   `json:"authorizedNamespace" yaml:"authorizedNamespace"`
+	AuthorizedIdentities []string `json:"authorizedIdentities" yaml:"authorizedIdentities"`
+	Propagate            bool     `json:"propagate" yaml:"propagate"`
+}
+
+// NewManifest returns the Manifest for ops. The cloud policy is generated for cloud which
+// is ProviderAWS, ProviderGCP or empty if the operator does not call a cloud API. namespace
+// is the Prisma namespace of the operator.
+func NewManifest(cloudProvider string, cloud Provider, namespace string, ops []types.Op) *Manifest {
+
+	manifest := &Manifest{
+		CloudProvider: cloudProvider,
+		Ops:           ops,
+		Prisma:        NewPrismaPolicy(namespace, ops),
+		Kubernetes:    NewClusterRole(ops),
+	}
+
+	switch cloud {
+
+	case ProviderAWS:
+		manifest.AWS = NewAWSPolicy(ops)
+
+	case ProviderGCP:
+		manifest.GCP = NewGCPRole(ops)
+
+	}
+
+	for _, provider := range []Provider{cloud, ProviderPrisma, ProviderKubernetes} {
+		if provider != "" {
+			manifest.Calls = append(manifest.Calls, Calls(provider, ops)...)
+		}
+	}
+
+	return manifest
+}
+
+// NewAWSPolicy returns the IAM policy for ops. The EC2 and EKS read actions do not support
+// resource level permissions so the resource is always *.
+func NewAWSPolicy(ops []types.Op) *AWSPolicy {
+	return &AWSPolicy{
+		Version: "2012-10-17",
+		Statement: []*AWSStatement{
+			{
+				Sid:      "CloudOperator",
+				Effect:   "Allow",
+				Action:   permissionList(Calls(ProviderAWS, ops)),
+				Resource: "*",
+			},
+		},
+	}
+}
+
+// NewGCPRole returns the custom role for ops
+func NewGCPRole(ops []types.Op) *GCPRole {
+	return &GCPRole{
+		Title:               "Cloud Operator",
+		Description:         "Permissions required by the Cloud Operator",
+		Stage:               "GA",
+		IncludedPermissions: permissionList(Calls(ProviderGCP, ops)),
+	}
+}
+
+// NewPrismaPolicy returns the API authorization policy for ops. Each authorized identity is
+// in the form identity:verb,verb.
+func NewPrismaPolicy(namespace string, ops []types.Op) *PrismaPolicy {
+
+	policy := &PrismaPolicy{
+		Name:                manifestName,
+		Description:         "Permissions required by the Cloud Operator",
+		AuthorizedNamespace: namespace,
+		Propagate:           true,
+	}
+
+	verbs := make(map[string][]string)
+	var identities []string
+
+	for _, call := range Calls(ProviderPrisma, ops) {
+		if _, ok := verbs[call.Permission]; !ok {
+			identities = append(identities, call.Permission)
+		}
+		verbs[call.Permission] = appendUnique(verbs[call.Permission], call.Verbs...)
+	}
+
+	for _, identity := range identities {
+		policy.AuthorizedIdentities = append(policy.AuthorizedIdentities, identity+":"+strings.Join(verbs[identity], ","))
+	}
+
+	return policy
+}
+
+// NewClusterRole returns the Kubernetes ClusterRole for ops or nil if ops do not call
+// Kubernetes. Rules for the same resource are merged.
+func NewClusterRole(ops []types.Op) *k8srbacv1.ClusterRole {
+
+	var rules []*k8srbacv1.PolicyRule
+	index := make(map[string]*k8srbacv1.PolicyRule)
+
+	for _, call := range Calls(ProviderKubernetes, ops) {
+
+		key := call.Group + "/" + call.Resource + "/" + call.Name
+
+		rule, ok := index[key]
+		if !ok {
+			rule = &k8srbacv1.PolicyRule{
+				APIGroups: []string{call.Group},
+				Resources: []string{call.Resource},
+			}
+			if call.Name != "" {
+				rule.ResourceNames = []string{call.Name}
+			}
+			index[key] = rule
+			rules = append(rules, rule)
+		}
+
+		rule.Verbs = appendUnique(rule.Verbs, call.Verbs...)
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	clusterRole := &k8srbacv1.ClusterRole{
+		TypeMeta: k8smetav1.TypeMeta{
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name: manifestName,
+		},
+	}
+
+	for _, rule := range rules {
+		clusterRole.Rules = append(clusterRole.Rules, *rule)
+	}
+
+	return clusterRole
+}
+
+// permissionList returns the unique permissions of calls in table order
+func permissionList(calls []*Call) []string {
+
+	var result []string
+
+	for _, call := range calls {
+		result = appendUnique(result, call.Permission)
+	}
+
+	return result
+}
+
+func appendUnique(list []string, v ...string) []string {
+
+	for _, s := range v {
+
+		found := false
+		for _, existing := range list {
+			if existing == s {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			list = append(list, s)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,204 @@
+package permissions
+
+import (
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// Provider is the API a call is made against
+type Provider string
+
+const (
+	// ProviderAWS AWS IAM actions
+	ProviderAWS Provider = "AWS"
+
+	// ProviderGCP GCP IAM permissions
+	ProviderGCP Provider = "GCP"
+
+	// ProviderPrisma Prisma API identities
+	ProviderPrisma Provider = "PRISMA"
+
+	// ProviderKubernetes Kubernetes resources
+	ProviderKubernetes Provider = "KUBERNETES"
+)
+
+// Call is a permission used by the operator. A call without ops is made on every run.
+type Call struct {
+	Provider Provider   `json:"provider" yaml:"provider"`
+	Ops      []types.Op `json:"ops,omitempty" yaml:"ops,omitempty"`
+
+	// Permission is the AWS action (ec2:DescribeVpcs), the GCP permission
+	// (compute.instances.list) or the Prisma identity (namespaces)
+	Permission string `json:"permission,omitempty" yaml:"permission,omitempty"`
+
+	// Verbs are the Prisma or Kubernetes verbs
+	Verbs []string `json:"verbs,omitempty" yaml:"verbs,omitempty"`
+
+	// Group, Resource, Namespace and Name are the Kubernetes resource attributes
+	Group     string `json:"group,omitempty" yaml:"group,omitempty"`
+	Resource  string `json:"resource,omitempty" yaml:"resource,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Name      string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// EnforcerNamespace scopes the call to the namespace the enforcer is installed in
+	EnforcerNamespace bool `json:"enforcerNamespace,omitempty" yaml:"enforcerNamespace,omitempty"`
+
+	// Source is the function making the call
+	Source string `json:"source" yaml:"source"`
+}
+
+var (
+	namespaceCreateOps = []types.Op{types.OpNamespaceComputeCreate, types.OpNamespaceKubeCreate}
+
+	namespaceDeleteOps = []types.Op{types.OpNamespaceRogueDelete, types.OpNamespaceComputeDelete, types.OpNamespaceKubeDelete,
+		types.OpNamespaceMigrateNames}
+
+	authOps = []types.Op{types.OpComputeAuth, types.OpKubeAuth, types.OpKubeWorkloadAuth}
+
+	kubeNetOps = []types.Op{types.OpKubeAPINet, types.OpKubeDNSNet, types.OpKubeNodesNet, types.OpKubeClusterNets}
+
+	// kubeOps connect to the clusters
+	kubeOps = []types.Op{types.OpKubeAuth, types.OpKubeWorkloadAuth, types.OpKubeAPINet, types.OpKubeDNSNet, types.OpKubeNodesNet,
+		types.OpKubeClusterNets, types.OpKubeEnforcer, types.OpKubeEnforcerUninstall}
+
+	enforcerOps = []types.Op{types.OpKubeEnforcer}
+
+	uninstallOps = []types.Op{types.OpKubeEnforcerUninstall}
+)
+
+// calls is every call made by the operator. The cloud caches are built on every run so
+// their calls have no ops. Update the table when a call is added to the code.
+var calls = []*Call{
+
+	// AWS
+
+	{Provider: ProviderAWS, Permission: "ec2:DescribeVpcs", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "ec2:DescribeSubnets", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "ec2:DescribeInstances", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "eks:ListClusters", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "eks:DescribeCluster", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "eks:ListNodegroups", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "eks:DescribeNodegroup", Source: "aws/operator/cache.init"},
+	{Provider: ProviderAWS, Permission: "eks:ListFargateProfiles", Source: "aws/operator/cache.listFargateProfiles"},
+	{Provider: ProviderAWS, Permission: "eks:DescribeFargateProfile", Source: "aws/operator/cache.init"},
+
+	// GCP
+
+	{Provider: ProviderGCP, Permission: "compute.instances.list", Source: "gcp/operator/cache.init"},
+	{Provider: ProviderGCP, Permission: "container.clusters.list", Source: "gcp/operator/cache.init"},
+	{Provider: ProviderGCP, Permission: "compute.networks.list", Source: "gcp/operator/cache.listNetworks"},
+	{Provider: ProviderGCP, Permission: "compute.subnetworks.list", Source: "gcp/operator/cache.listNetworks"},
+	{Provider: ProviderGCP, Permission: "compute.projects.get", Source: "gcp/operator/cache.init"},
+	{Provider: ProviderGCP, Permission: "compute.instanceGroups.list", Source: "gcp/operator/cache.init"},
+	{Provider: ProviderGCP, Ops: kubeOps, Permission: "container.clusters.get", Source: "gcp/operator.getKubernetesClientset"},
+
+	// Prisma
+
+	{Provider: ProviderPrisma, Permission: "namespaces", Verbs: []string{"get"}, Source: "common/layout.NewClient"},
+	{Provider: ProviderPrisma, Ops: namespaceCreateOps, Permission: "namespaces", Verbs: []string{"post", "put"}, Source: "common/processors.NamespaceProcessor"},
+	{Provider: ProviderPrisma, Ops: namespaceDeleteOps, Permission: "namespaces", Verbs: []string{"put", "delete"}, Source: "common/processors.NamespaceProcessor"},
+	{Provider: ProviderPrisma, Ops: append(append([]types.Op{types.OpDHCP, types.OpInfraServices, types.OpKubeEnforcerUninstall}, authOps...), kubeNetOps...),
+		Permission: "import", Verbs: []string{"post"}, Source: "ImportPrismaConfig"},
+	{Provider: ProviderPrisma, Ops: append([]types.Op{types.OpDHCP, types.OpInfraServices, types.OpKubeEnforcerUninstall}, kubeNetOps...),
+		Permission: "externalnetworks", Verbs: []string{"get", "post", "put", "delete"}, Source: "ImportPrismaConfig"},
+	{Provider: ProviderPrisma, Ops: []types.Op{types.OpDHCP, types.OpInfraServices},
+		Permission: "networkrulesetpolicies", Verbs: []string{"get", "post", "put", "delete"}, Source: "ImportPrismaConfig"},
+	{Provider: ProviderPrisma, Ops: authOps,
+		Permission: "apiauthorizationpolicies", Verbs: []string{"get", "post", "put", "delete"}, Source: "ImportPrismaConfig"},
+
+	// Kubernetes
+
+	{Provider: ProviderKubernetes, Ops: []types.Op{types.OpKubeDNSNet}, Verbs: []string{"get"},
+		Resource: "services", Namespace: "kube-system", Name: "kube-dns", Source: "common/processors.addKubeDNSNet"},
+	{Provider: ProviderKubernetes, Ops: []types.Op{types.OpKubeNodesNet}, Verbs: []string{"list"},
+		Resource: "nodes", Source: "common/processors.addKubeNodesNet"},
+	{Provider: ProviderKubernetes, Ops: []types.Op{types.OpKubeWorkloadAuth}, Verbs: []string{"list"},
+		Resource: "serviceaccounts", Source: "common/processors.addKubeWorkloadAuth"},
+
+	// Server side apply creates missing objects so both create and patch are required. Get
+	// is used by drift detection and the rollout.
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"get", "create", "patch"},
+		Resource: "namespaces", Source: "common/processors.installEnforcer"},
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"get", "create", "patch"},
+		Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Source: "common/processors.installEnforcer"},
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"get", "create", "patch"},
+		Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", Source: "common/processors.installEnforcer"},
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"get", "create", "patch"},
+		Resource: "serviceaccounts", Source: "common/processors.installEnforcer"},
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"get", "create", "patch"},
+		Group: "apps", Resource: "daemonsets", Source: "common/processors.installEnforcer"},
+
+	// The enforcer ClusterRole grants permissions the operator does not hold itself
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"escalate", "bind"},
+		Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Source: "common/processors.installEnforcer"},
+
+	{Provider: ProviderKubernetes, Ops: enforcerOps, Verbs: []string{"list"},
+		Resource: "pods", EnforcerNamespace: true, Source: "common/processors.podReasons"},
+
+	{Provider: ProviderKubernetes, Ops: uninstallOps, Verbs: []string{"get", "delete"},
+		Group: "apps", Resource: "daemonsets", Source: "common/processors.uninstallEnforcer"},
+	{Provider: ProviderKubernetes, Ops: uninstallOps, Verbs: []string{"get", "delete"},
+		Resource: "serviceaccounts", Source: "common/processors.uninstallEnforcer"},
+	{Provider: ProviderKubernetes, Ops: uninstallOps, Verbs: []string{"get", "delete"},
+		Group: "rbac.authorization.k8s.io", Resource: "clusterrolebindings", Source: "common/processors.uninstallEnforcer"},
+	{Provider: ProviderKubernetes, Ops: uninstallOps, Verbs: []string{"get", "delete"},
+		Group: "rbac.authorization.k8s.io", Resource: "clusterroles", Source: "common/processors.uninstallEnforcer"},
+	{Provider: ProviderKubernetes, Ops: uninstallOps, Verbs: []string{"get", "delete"},
+		Resource: "namespaces", Source: "common/processors.uninstallEnforcer"},
+}
+
+// Calls returns the calls made against provider when ops are enabled
+func Calls(provider Provider, ops []types.Op) []*Call {
+
+	var result []*Call
+
+	for _, call := range calls {
+		if call.Provider == provider && call.enabled(ops) {
+			result = append(result, call)
+		}
+	}
+
+	return result
+}
+
+// HasOp returns true if the call is made by op
+func (t *Call) HasOp(op types.Op) bool {
+	for _, v := range t.Ops {
+		if v == op {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledOps returns the ops in ops that make the call. A call without ops is made by every op.
+func (t *Call) EnabledOps(ops []types.Op) []types.Op {
+
+	if len(t.Ops) == 0 {
+		return ops
+	}
+
+	var result []types.Op
+
+	for _, op := range ops {
+		if t.HasOp(op) {
+			result = append(result, op)
+		}
+	}
+
+	return result
+}
+
+func (t *Call) enabled(ops []types.Op) bool {
+
+	if len(t.Ops) == 0 {
+		return true
+	}
+
+	for _, op := range ops {
+		if t.HasOp(op) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,38 @@
+package permissions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestCallEnabledOps(t *testing.T) {
+
+	ops := []types.Op{types.OpComputeAuth, types.OpKubeAuth, types.OpKubeEnforcer}
+
+	tests := map[string]struct {
+		call *Call
+		want []types.Op
+	}{
+		"made by every op": {
+			call: &Call{},
+			want: ops,
+		},
+		"made by some ops": {
+			call: &Call{Ops: kubeOps},
+			want: []types.Op{types.OpKubeAuth, types.OpKubeEnforcer},
+		},
+		"not enabled": {
+			call: &Call{Ops: uninstallOps},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.call.EnabledOps(ops); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
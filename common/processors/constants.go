@@ -3,7 +3,16 @@ package processors
 const (
 	importLabel = "Cloud-Operator-Kubernetes"
 
+	// fieldManager is the server side apply field manager of the objects the operator owns
+	fieldManager = "cloud-operator"
+
 	namespaceAnnotationKey = importLabel
+
+	// namespaceTombstoneKey holds the time (RFC3339) a namespace was first found unused
+	namespaceTombstoneKey = "Cloud-Operator-Tombstone"
+
+	// namespaceTombstoneRunsKey holds the number of consecutive runs a namespace was found unused
+	namespaceTombstoneRunsKey = "Cloud-Operator-Tombstone-Runs"
 )
 
 //var namespaceAnnotationValue = []string{"a"}
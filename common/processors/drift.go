@@ -0,0 +1,201 @@
+package processors
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// driftIgnoredKeys are top level keys not compared. Typed objects returned by the API do
+// not carry their type meta and status is owned by the cluster.
+var driftIgnoredKeys = map[string]bool{
+	"kind":       true,
+	"apiVersion": true,
+	"status":     true,
+}
+
+// Drift returns the drift found on the enforcer objects or nil if none was found or drift
+// detection is off
+func (t *KubeProcessor) Drift() []*types.DriftReport {
+	return t.drift
+}
+
+// detectDrift compares the live objects with the desired objects. Only the fields set in the
+// desired object are compared so defaults added by the API server are not drift. Objects the
+// operator does not manage are reported as unmanaged and not compared.
+func (t *KubeProcessor) detectDrift(ctx context.Context, objects []*managedObject) ([]*types.DriftReport, error) {
+
+	zap.L().Debug("entering detectDrift")
+
+	var result []*types.DriftReport
+
+	// Report in installation order
+	for i := len(objects) - 1; i >= 0; i-- {
+
+		object := objects[i]
+
+		if object.name == "" {
+			continue
+		}
+
+		live, err := object.get(ctx)
+		if k8serrors.IsNotFound(err) {
+			result = append(result, types.NewDriftReport(object.kind, object.name, types.DriftStatusMissing))
+			continue
+		}
+		if err != nil {
+			zap.L().Debug("returning detectDrift with error(s)")
+			return nil, err
+		}
+
+		if !isManaged(live) {
+			zap.L().Info(fmt.Sprintf("%s %s on cluster %s is not managed by %s", object.kind, object.name, t.name, fieldManager))
+			result = append(result, types.NewDriftReport(object.kind, object.name, types.DriftStatusUnmanaged))
+			continue
+		}
+
+		desiredMap, err := toMap(object.desired)
+		if err != nil {
+			zap.L().Debug("returning detectDrift with error(s)")
+			return nil, err
+		}
+
+		liveMap, err := toMap(live)
+		if err != nil {
+			zap.L().Debug("returning detectDrift with error(s)")
+			return nil, err
+		}
+
+		for key := range driftIgnoredKeys {
+			delete(desiredMap, key)
+		}
+
+		var differences []string
+		compareDrift("", desiredMap, liveMap, &differences)
+
+		if len(differences) > 0 {
+			zap.L().Info(fmt.Sprintf("%s %s on cluster %s has drifted: %v", object.kind, object.name, t.name, differences))
+			result = append(result, types.NewDriftReport(object.kind, object.name, types.DriftStatusDrifted).AddDifferences(differences...))
+		}
+	}
+
+	zap.L().Debug("returning detectDrift")
+	return result, nil
+}
+
+// skipApply returns the kinds of the objects that are not applied. Objects owned by another
+// field manager are never applied. In detect mode drifted objects are left as they are; only
+// missing objects are applied.
+func skipApply(driftMode types.DriftMode, drift []*types.DriftReport) map[string]bool {
+
+	result := make(map[string]bool)
+
+	for _, report := range drift {
+
+		switch report.Status {
+
+		case types.DriftStatusUnmanaged:
+			result[report.Kind] = true
+
+		case types.DriftStatusDrifted:
+			if driftMode == types.DriftModeDetect {
+				result[report.Kind] = true
+			}
+
+		}
+	}
+
+	return result
+}
+
+// compareDrift appends a difference for each value of desired that is missing or different in live
+func compareDrift(path string, desired, live interface{}, differences *[]string) {
+
+	switch desiredValue := desired.(type) {
+
+	case map[string]interface{}:
+
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			*differences = append(*differences, fmt.Sprintf("%s: desired %s, live %s", pathOrRoot(path), toJSON(desired), toJSON(live)))
+			return
+		}
+
+		keys := make([]string, 0, len(desiredValue))
+		for key := range desiredValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			compareDrift(joinField(path, key), desiredValue[key], liveValue[key], differences)
+		}
+
+	case []interface{}:
+
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(desiredValue) {
+			*differences = append(*differences, fmt.Sprintf("%s: desired %s, live %s", pathOrRoot(path), toJSON(desired), toJSON(live)))
+			return
+		}
+
+		for i := range desiredValue {
+			compareDrift(fmt.Sprintf("%s[%d]", path, i), desiredValue[i], liveValue[i], differences)
+		}
+
+	default:
+
+		if !reflect.DeepEqual(desired, live) {
+			*differences = append(*differences, fmt.Sprintf("%s: desired %s, live %s", pathOrRoot(path), toJSON(desired), toJSON(live)))
+		}
+
+	}
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]interface{})
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func toJSON(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
+
+func joinField(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
@@ -0,0 +1,133 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	k8scorev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// liveNamespace returns a live namespace with labels whose fields are managed by manager
+func liveNamespace(manager string, labels map[string]string) *k8scorev1.Namespace {
+	return &k8scorev1.Namespace{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name:          "aporeto",
+			Labels:        labels,
+			ManagedFields: []k8smetav1.ManagedFieldsEntry{{Manager: manager}},
+		},
+	}
+}
+
+// namespaceObject returns the managed object of the aporeto namespace whose live object
+// is returned by get
+func namespaceObject(get func(ctx context.Context) (k8smetav1.Object, error)) *managedObject {
+	return &managedObject{
+		kind:     "Namespace",
+		resource: "namespaces",
+		name:     "aporeto",
+		desired:  corev1ac.Namespace("aporeto").WithLabels(map[string]string{"app": "enforcer"}),
+		get:      get,
+	}
+}
+
+func TestDetectDrift(t *testing.T) {
+
+	notFound := k8serrors.NewNotFound(schema.GroupResource{Resource: "namespaces"}, "aporeto")
+
+	tests := map[string]struct {
+		live    k8smetav1.Object
+		err     error
+		want    []*types.DriftReport
+		wantErr bool
+	}{
+		"missing": {
+			err:  notFound,
+			want: []*types.DriftReport{types.NewDriftReport("Namespace", "aporeto", types.DriftStatusMissing)},
+		},
+		"unchanged": {
+			live: liveNamespace(fieldManager, map[string]string{"app": "enforcer"}),
+		},
+		"defaults added by the server are not drift": {
+			live: liveNamespace(fieldManager, map[string]string{"app": "enforcer", "kubernetes.io/metadata.name": "aporeto"}),
+		},
+		"drifted": {
+			live: liveNamespace(fieldManager, map[string]string{"app": "other"}),
+			want: []*types.DriftReport{types.NewDriftReport("Namespace", "aporeto", types.DriftStatusDrifted).
+				AddDifferences(`metadata.labels.app: desired "enforcer", live "other"`)},
+		},
+		"owned by another manager": {
+			live: liveNamespace("kubectl", map[string]string{"app": "other"}),
+			want: []*types.DriftReport{types.NewDriftReport("Namespace", "aporeto", types.DriftStatusUnmanaged)},
+		},
+		"get fails": {
+			err:     fmt.Errorf("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			processor := &KubeProcessor{name: "cluster1"}
+
+			object := namespaceObject(func(ctx context.Context) (k8smetav1.Object, error) {
+				return test.live, test.err
+			})
+
+			got, err := processor.detectDrift(context.Background(), []*managedObject{object})
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %s, want %s", toJSON(got), toJSON(test.want))
+			}
+		})
+	}
+}
+
+func TestSkipApply(t *testing.T) {
+
+	drift := []*types.DriftReport{
+		types.NewDriftReport("Namespace", "aporeto", types.DriftStatusUnmanaged),
+		types.NewDriftReport("ClusterRole", "aporeto", types.DriftStatusDrifted),
+		types.NewDriftReport("DaemonSet", "enforcerd", types.DriftStatusMissing),
+	}
+
+	tests := map[string]struct {
+		mode  types.DriftMode
+		drift []*types.DriftReport
+		want  map[string]bool
+	}{
+		"no drift": {
+			mode: types.DriftModeDetect,
+			want: map[string]bool{},
+		},
+		"detect applies missing objects only": {
+			mode:  types.DriftModeDetect,
+			drift: drift,
+			want:  map[string]bool{"Namespace": true, "ClusterRole": true},
+		},
+		"enforce skips unmanaged objects only": {
+			mode:  types.DriftModeEnforce,
+			drift: drift,
+			want:  map[string]bool{"Namespace": true},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := skipApply(test.mode, test.drift); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
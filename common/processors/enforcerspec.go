@@ -0,0 +1,131 @@
+package processors
+
+import (
+	"encoding/json"
+	"strings"
+
+	k8sappsv1 "k8s.io/api/apps/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	appsv1ac "k8s.io/client-go/applyconfigurations/apps/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// overlayEnforcerSpec returns daemonset with spec overlaid using a strategic merge patch.
+// Containers are merged by name so only the resources and image of the built containers
+// are replaced.
+func overlayEnforcerSpec(daemonset *appsv1ac.DaemonSetApplyConfiguration, spec *types.EnforcerSpec) (*appsv1ac.DaemonSetApplyConfiguration, error) {
+
+	if spec == nil || spec.IsEmpty() {
+		return daemonset, nil
+	}
+
+	original, err := json.Marshal(daemonset)
+	if err != nil {
+		return nil, err
+	}
+
+	var current k8sappsv1.DaemonSet
+	err = json.Unmarshal(original, &current)
+	if err != nil {
+		return nil, err
+	}
+
+	podSpec := make(map[string]interface{})
+
+	// Tolerations have no merge key so the list is replaced; the built ones are kept
+	if len(spec.Tolerations) > 0 {
+		podSpec["tolerations"] = append(current.Spec.Template.Spec.Tolerations, spec.Tolerations...)
+	}
+
+	if len(spec.NodeSelector) > 0 {
+		podSpec["nodeSelector"] = spec.NodeSelector
+	}
+
+	if spec.PriorityClassName != "" {
+		podSpec["priorityClassName"] = spec.PriorityClassName
+	}
+
+	if len(spec.ImagePullSecrets) > 0 {
+		var secrets []k8scorev1.LocalObjectReference
+		for _, name := range spec.ImagePullSecrets {
+			secrets = append(secrets, k8scorev1.LocalObjectReference{Name: name})
+		}
+		podSpec["imagePullSecrets"] = secrets
+	}
+
+	if containers := containerPatches(current.Spec.Template.Spec.InitContainers, spec); len(containers) > 0 {
+		podSpec["initContainers"] = containers
+	}
+
+	if containers := containerPatches(current.Spec.Template.Spec.Containers, spec); len(containers) > 0 {
+		podSpec["containers"] = containers
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": podSpec,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := strategicpatch.StrategicMergePatch(original, patch, k8sappsv1.DaemonSet{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &appsv1ac.DaemonSetApplyConfiguration{}
+	err = json.Unmarshal(merged, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func containerPatches(containers []k8scorev1.Container, spec *types.EnforcerSpec) []map[string]interface{} {
+
+	if spec.Resources == nil && spec.ImageRegistry == "" {
+		return nil
+	}
+
+	var result []map[string]interface{}
+
+	for _, container := range containers {
+
+		patch := map[string]interface{}{
+			"name": container.Name,
+		}
+
+		if spec.Resources != nil {
+			patch["resources"] = spec.Resources
+		}
+
+		if spec.ImageRegistry != "" && container.Image != "" {
+			patch["image"] = mirrorImage(container.Image, spec.ImageRegistry)
+		}
+
+		result = append(result, patch)
+	}
+
+	return result
+}
+
+// mirrorImage replaces the registry of image with registry. An image without a registry
+// (Docker Hub) keeps its full repository path.
+func mirrorImage(image, registry string) string {
+
+	registry = strings.TrimSuffix(registry, "/")
+
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		return registry + "/" + parts[1]
+	}
+
+	return registry + "/" + image
+}
@@ -0,0 +1,222 @@
+package processors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	builder "github.com/aporeto-se/enforcerd-kube-builder"
+	"go.uber.org/zap"
+	"sigs.k8s.io/yaml"
+
+	"github.com/aporeto-se/cloud-operator/common/layout"
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+	exportYAMLFile          = "enforcer.yaml"
+	exportKustomizationFile = "kustomization.yaml"
+
+	// exportMarkerFile records the namespace a directory was exported for. Only directories
+	// with a matching marker are removed.
+	exportMarkerFile = ".cloud-operator"
+)
+
+// exportedObject is an enforcer object and the file it is written to in Kustomize format
+type exportedObject struct {
+	file   string
+	object interface{}
+}
+
+// Export returns the directory the enforcer objects were written to or an empty string if
+// they were applied
+func (t *KubeProcessor) Export() string {
+	return t.export
+}
+
+// exportDir returns the directory of the cluster below the export directory. It follows the
+// path of the cluster namespace which is unique across clusters and cloud accounts.
+func (t *KubeProcessor) exportDir() (string, error) {
+
+	segments := layout.Segments(t.namespace)
+	if len(segments) == 0 {
+		return "", fmt.Errorf("the namespace of cluster %s is required to export", t.name)
+	}
+
+	dir := []string{t.cloudOperatorConfig.KubeExportDir}
+	for _, segment := range segments {
+		dir = append(dir, layout.Sanitize(segment))
+	}
+
+	return filepath.Join(dir...), nil
+}
+
+// checkExportDir returns true if dir exists. An error is returned if it exists but was not
+// exported for the namespace of the cluster so that it is never removed.
+func (t *KubeProcessor) checkExportDir(dir string) (bool, error) {
+
+	_, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, exportMarkerFile))
+	if os.IsNotExist(err) {
+		return true, fmt.Errorf("refusing to remove %s as it was not exported by the operator", dir)
+	}
+	if err != nil {
+		return true, err
+	}
+
+	if string(data) != t.namespace {
+		return true, fmt.Errorf("refusing to remove %s as it was exported for namespace %s", dir, string(data))
+	}
+
+	return true, nil
+}
+
+// exportEnforcer writes the objects of daemonset to the export directory of the cluster in
+// the configured format. The directory is owned by the operator and its previous content
+// is replaced so objects removed from the builder are also removed from the tree. A directory
+// not exported for the namespace of the cluster is never replaced.
+func (t *KubeProcessor) exportEnforcer(daemonset *builder.Daemonset) (string, error) {
+
+	zap.L().Debug("entering exportEnforcer")
+
+	objects := []*exportedObject{
+		{file: "namespace.yaml", object: daemonset.Namespace},
+		{file: "clusterrole.yaml", object: daemonset.ClusterRole},
+		{file: "clusterrolebinding.yaml", object: daemonset.ClusterRoleBinding},
+		{file: "serviceaccount.yaml", object: daemonset.ServiceAccount},
+		{file: "daemonset.yaml", object: daemonset.DaemonSet},
+	}
+
+	files := make(map[string][]byte)
+
+	switch format := t.cloudOperatorConfig.GetKubeExportFormat(); format {
+
+	case types.ExportFormatYAML:
+
+		var data []byte
+
+		for _, object := range objects {
+			b, err := yaml.Marshal(object.object)
+			if err != nil {
+				zap.L().Debug("returning exportEnforcer with error(s)")
+				return "", err
+			}
+			data = append(data, []byte("---\n")...)
+			data = append(data, b...)
+		}
+
+		files[exportYAMLFile] = data
+
+	case types.ExportFormatKustomize:
+
+		var resources []string
+
+		for _, object := range objects {
+			b, err := yaml.Marshal(object.object)
+			if err != nil {
+				zap.L().Debug("returning exportEnforcer with error(s)")
+				return "", err
+			}
+			files[object.file] = b
+			resources = append(resources, object.file)
+		}
+
+		b, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": "kustomize.config.k8s.io/v1beta1",
+			"kind":       "Kustomization",
+			"resources":  resources,
+		})
+		if err != nil {
+			zap.L().Debug("returning exportEnforcer with error(s)")
+			return "", err
+		}
+
+		files[exportKustomizationFile] = b
+
+	default:
+		zap.L().Debug("returning exportEnforcer with error(s)")
+		return "", fmt.Errorf("export format %s is not supported", format)
+
+	}
+
+	files[exportMarkerFile] = []byte(t.namespace)
+
+	dir, err := t.exportDir()
+	if err != nil {
+		zap.L().Debug("returning exportEnforcer with error(s)")
+		return "", err
+	}
+
+	exists, err := t.checkExportDir(dir)
+	if err != nil {
+		zap.L().Debug("returning exportEnforcer with error(s)")
+		return "", err
+	}
+
+	if exists {
+		err = os.RemoveAll(dir)
+		if err != nil {
+			zap.L().Debug("returning exportEnforcer with error(s)")
+			return "", err
+		}
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		zap.L().Debug("returning exportEnforcer with error(s)")
+		return "", err
+	}
+
+	for file, data := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, file), data, 0644)
+		if err != nil {
+			zap.L().Debug("returning exportEnforcer with error(s)")
+			return "", err
+		}
+	}
+
+	zap.L().Info(fmt.Sprintf("Exported enforcer objects for cluster %s to %s", t.name, dir))
+
+	zap.L().Debug("returning exportEnforcer")
+	return dir, nil
+}
+
+// unexportEnforcer removes the export directory of the cluster. Returns true if it existed.
+func (t *KubeProcessor) unexportEnforcer() (bool, error) {
+
+	zap.L().Debug("entering unexportEnforcer")
+
+	dir, err := t.exportDir()
+	if err != nil {
+		zap.L().Debug("returning unexportEnforcer with error(s)")
+		return false, err
+	}
+
+	exists, err := t.checkExportDir(dir)
+	if err != nil {
+		zap.L().Debug("returning unexportEnforcer with error(s)")
+		return false, err
+	}
+
+	if !exists {
+		zap.L().Debug("returning unexportEnforcer; nothing to remove")
+		return false, nil
+	}
+
+	err = os.RemoveAll(dir)
+	if err != nil {
+		zap.L().Debug("returning unexportEnforcer with error(s)")
+		return false, err
+	}
+
+	zap.L().Debug("returning unexportEnforcer")
+	return true, nil
+}
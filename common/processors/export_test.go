@@ -0,0 +1,145 @@
+package processors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	builder "github.com/aporeto-se/enforcerd-kube-builder"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func exportProcessor(dir, name, namespace string) *KubeProcessor {
+	return &KubeProcessor{
+		name:                name,
+		namespace:           namespace,
+		cloudOperatorConfig: (&types.CloudOperatorConfig{}).SetKubeExportDir(dir),
+	}
+}
+
+func exportDaemonset() *builder.Daemonset {
+	return &builder.Daemonset{Namespace: corev1ac.Namespace("aporeto")}
+}
+
+func TestExportDirIsUniquePerNamespace(t *testing.T) {
+
+	dir := t.TempDir()
+
+	// Both cluster names sanitize to prod-1
+	first := exportProcessor(dir, "prod.1", "/tenant/account1/prod-1")
+	second := exportProcessor(dir, "prod_1", "/tenant/account2/prod-1")
+
+	firstDir, err := first.exportEnforcer(exportDaemonset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secondDir, err := second.exportEnforcer(exportDaemonset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if firstDir != filepath.Join(dir, "tenant", "account1", "prod-1") || secondDir != filepath.Join(dir, "tenant", "account2", "prod-1") {
+		t.Fatalf("got %s and %s", firstDir, secondDir)
+	}
+
+	// Exporting again replaces only the directory of the cluster
+	_, err = first.exportEnforcer(exportDaemonset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(secondDir, exportYAMLFile)); err != nil {
+		t.Fatalf("export of the second cluster was removed: %s", err)
+	}
+}
+
+func TestExportRefusesForeignDir(t *testing.T) {
+
+	tests := map[string]struct {
+		marker string
+	}{
+		"no marker":                 {},
+		"marker of another cluster": {marker: "/tenant/account1/other"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			dir := t.TempDir()
+			processor := exportProcessor(dir, "prod", "/tenant/account1/prod")
+
+			clusterDir, err := processor.exportDir()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = os.MkdirAll(clusterDir, 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			manual := filepath.Join(clusterDir, "manual.yaml")
+			err = ioutil.WriteFile(manual, []byte("kind: ConfigMap\n"), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if test.marker != "" {
+				err = ioutil.WriteFile(filepath.Join(clusterDir, exportMarkerFile), []byte(test.marker), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := processor.exportEnforcer(exportDaemonset()); err == nil {
+				t.Fatalf("expected export to refuse to replace %s", clusterDir)
+			}
+
+			if _, err := processor.unexportEnforcer(); err == nil {
+				t.Fatalf("expected unexport to refuse to remove %s", clusterDir)
+			}
+
+			if _, err := os.Stat(manual); err != nil {
+				t.Fatalf("foreign file was removed: %s", err)
+			}
+		})
+	}
+}
+
+func TestUnexport(t *testing.T) {
+
+	dir := t.TempDir()
+	processor := exportProcessor(dir, "prod", "/tenant/account1/prod")
+
+	removed, err := processor.unexportEnforcer()
+	if err != nil || removed {
+		t.Fatalf("expected nothing to remove, got %t %v", removed, err)
+	}
+
+	clusterDir, err := processor.exportEnforcer(exportDaemonset())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err = processor.unexportEnforcer()
+	if err != nil || !removed {
+		t.Fatalf("expected the export to be removed, got %t %v", removed, err)
+	}
+
+	if _, err := os.Stat(clusterDir); !os.IsNotExist(err) {
+		t.Fatalf("%s still exists", clusterDir)
+	}
+}
+
+func TestExportRequiresNamespace(t *testing.T) {
+
+	processor := exportProcessor(t.TempDir(), "prod", "")
+
+	if _, err := processor.exportEnforcer(exportDaemonset()); err == nil {
+		t.Fatalf("expected error without a namespace")
+	}
+}
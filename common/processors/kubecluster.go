@@ -4,30 +4,44 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sort"
 
 	builder "github.com/aporeto-se/enforcerd-kube-builder"
 	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
 	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
+	k8scorev1 "k8s.io/api/core/v1"
 	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 
+	"github.com/aporeto-se/cloud-operator/common/auth"
 	"github.com/aporeto-se/cloud-operator/common/types"
 )
 
 // KubeProcessor Kubernetes processor
 type KubeProcessor struct {
 	name                string
+	namespace           string
 	prismaClient        *prisma_api.Client
 	cloudOperatorConfig *types.CloudOperatorConfig
 
 	protectConfig   bool
 	importLabel     string
 	prismaAPIConfig *prisma_types.PrismaConfig
+	rollout         *types.RolloutReport
+	drift           []*types.DriftReport
+	export          string
+	skipEnforcer    string
+	tags            map[string]string
+
+	workloadIdentity auth.WorkloadIdentityFunc
 
 	Endpoint                   string
 	CidrBlocks                 []string
+	NodeCidrBlocks             []string
+	PodCidrBlocks              []string
+	ServiceCidrBlocks          []string
 	KubernetesClientset        *kubernetes.Clientset
 	KubernetesDaemonsetBuilder *builder.Builder
 }
@@ -65,6 +79,7 @@ func NewKubeProcessor(name string, cloudOperatorConfig *types.CloudOperatorConfi
 
 	return &KubeProcessor{
 		name:                name,
+		namespace:           prismaClient.GetNamespacePath(),
 		cloudOperatorConfig: cloudOperatorConfig,
 		prismaClient:        prismaClient,
 		importLabel:         importLabel + "-" + name,
@@ -86,7 +101,45 @@ func (t *KubeProcessor) SetCidrBlocks(cidrBlocks []string) *KubeProcessor {
 
 // AddCidrBlocks adds attribute and returns self
 func (t *KubeProcessor) AddCidrBlocks(cidrBlocks ...string) *KubeProcessor {
-	t.CidrBlocks = append(t.CidrBlocks, cidrBlocks...)
+	t.CidrBlocks = appendNonEmpty(t.CidrBlocks, cidrBlocks...)
+	return t
+}
+
+// AddNodeCidrBlocks adds the CIDR blocks of the node subnets and returns self
+func (t *KubeProcessor) AddNodeCidrBlocks(cidrBlocks ...string) *KubeProcessor {
+	t.NodeCidrBlocks = appendNonEmpty(t.NodeCidrBlocks, cidrBlocks...)
+	return t
+}
+
+// AddPodCidrBlocks adds the CIDR blocks pods are assigned from and returns self
+func (t *KubeProcessor) AddPodCidrBlocks(cidrBlocks ...string) *KubeProcessor {
+	t.PodCidrBlocks = appendNonEmpty(t.PodCidrBlocks, cidrBlocks...)
+	return t
+}
+
+// AddServiceCidrBlocks adds the CIDR blocks services are assigned from and returns self
+func (t *KubeProcessor) AddServiceCidrBlocks(cidrBlocks ...string) *KubeProcessor {
+	t.ServiceCidrBlocks = appendNonEmpty(t.ServiceCidrBlocks, cidrBlocks...)
+	return t
+}
+
+// SetTags sets the cloud tags or labels of the cluster and returns self
+func (t *KubeProcessor) SetTags(tags map[string]string) *KubeProcessor {
+	t.tags = tags
+	return t
+}
+
+// SetSkipEnforcer sets the reason the enforcer can not run on the cluster and returns self.
+// If set the enforcer objects are neither applied nor exported.
+func (t *KubeProcessor) SetSkipEnforcer(reason string) *KubeProcessor {
+	t.skipEnforcer = reason
+	return t
+}
+
+// SetWorkloadIdentity sets the function mapping annotated ServiceAccounts to cloud identities
+// for the KUBE_WORKLOAD_AUTH op and returns self
+func (t *KubeProcessor) SetWorkloadIdentity(workloadIdentity auth.WorkloadIdentityFunc) *KubeProcessor {
+	t.workloadIdentity = workloadIdentity
 	return t
 }
 
@@ -123,7 +176,17 @@ func (t *KubeProcessor) Process(ctx context.Context) error {
 		return err
 	}
 
-	err = t.addKubeAPINet()
+	t.addKubeAPINet()
+
+	t.addKubeClusterNets()
+
+	err = t.addKubeNodesNet(ctx)
+	if err != nil {
+		zap.L().Debug("returning Process with error(s)")
+		return err
+	}
+
+	err = t.addKubeWorkloadAuth(ctx)
 	if err != nil {
 		zap.L().Debug("returning Process with error(s)")
 		return err
@@ -146,6 +209,15 @@ func (t *KubeProcessor) Process(ctx context.Context) error {
 		return err
 	}
 
+	// The cluster is in scope but the enforcer is no longer wanted
+	if !t.cloudOperatorConfig.HasOp(types.OpKubeEnforcer) && t.cloudOperatorConfig.HasOp(types.OpKubeEnforcerUninstall) {
+		_, err = t.uninstallEnforcer(ctx)
+		if err != nil {
+			zap.L().Debug("returning Process with error(s)")
+			return err
+		}
+	}
+
 	zap.L().Debug("returning Process")
 	return nil
 }
@@ -216,33 +288,212 @@ func (t *KubeProcessor) addKubeDNSNet(ctx context.Context) error {
 	return nil
 }
 
-func (t *KubeProcessor) addKubeAPINet() error {
+// addKubeAPINet publishes the "cluster networks" external network. It is kept for the
+// policies that reference it; new policies should use the KUBE_CLUSTER_NETS networks.
+func (t *KubeProcessor) addKubeAPINet() {
 
 	zap.L().Debug("entering addKubeAPINet")
 
 	if !t.cloudOperatorConfig.HasOp(types.OpKubeAPINet) {
 		zap.L().Debug("returing addKubeAPINet option is disabled")
+		return
+	}
+
+	if len(t.CidrBlocks) <= 0 {
+		zap.L().Warn(fmt.Sprintf("cluster %s has no CIDR blocks specified; cluster networks is not published", t.name))
+		zap.L().Debug("returing addKubeAPINet")
+		return
+	}
+
+	t.initPrismaAPIConfig()
+
+	t.prismaAPIConfig.AddExternalnetwork(
+		prisma_types.NewExternalnetwork("cluster networks").
+			SetDescription("auto-generated by Cloud Operator").
+			SetProtected(t.protectConfig).SetEntries(t.CidrBlocks))
+
+	zap.L().Debug("returing addKubeAPINet")
+}
+
+// addKubeClusterNets publishes the workload ranges of the cluster. Each range is a separate
+// external network so policies can select them individually by name ($name=Kube Pods,
+// $name=Kube Services or $name=Kube Node Subnets) within the cluster namespace.
+func (t *KubeProcessor) addKubeClusterNets() {
+
+	zap.L().Debug("entering addKubeClusterNets")
+
+	if !t.cloudOperatorConfig.HasOp(types.OpKubeClusterNets) {
+		zap.L().Debug("returing addKubeClusterNets option is disabled")
+		return
+	}
+
+	t.initPrismaAPIConfig()
+
+	networks := []struct {
+		name       string
+		cidrBlocks []string
+	}{
+		{"Kube Pods", t.PodCidrBlocks},
+		{"Kube Services", t.ServiceCidrBlocks},
+		{"Kube Node Subnets", t.NodeCidrBlocks},
+	}
+
+	for _, network := range networks {
+
+		if len(network.cidrBlocks) == 0 {
+			zap.L().Debug(fmt.Sprintf("No CIDR blocks for %s for cluster %s", network.name, t.name))
+			continue
+		}
+
+		zap.L().Debug(fmt.Sprintf("Creating external network for %s (%v) for cluster %s", network.name, network.cidrBlocks, t.name))
+		t.prismaAPIConfig.AddExternalnetwork(
+			prisma_types.NewExternalnetwork(network.name).
+				SetDescription("auto-generated by Cloud Operator").
+				SetProtected(t.protectConfig).
+				SetPropagate(true).
+				SetEntries(network.cidrBlocks))
+	}
+
+	zap.L().Debug("returing addKubeClusterNets")
+}
+
+func (t *KubeProcessor) addKubeNodesNet(ctx context.Context) error {
+
+	zap.L().Debug("entering addKubeNodesNet")
+
+	if !t.cloudOperatorConfig.HasOp(types.OpKubeNodesNet) {
+		zap.L().Debug("returing addKubeNodesNet option is disabled")
 		return nil
 	}
 
+	if t.KubernetesClientset == nil {
+		zap.L().Debug("returing addKubeNodesNet with error(s)")
+		return fmt.Errorf("kubernetesClientset is required")
+	}
+
 	t.initPrismaAPIConfig()
 
-	if t.CidrBlocks == nil {
-		zap.L().Debug("returing addKubeAPINet with error(s)")
-		return fmt.Errorf("cluster has no CIDR blocks specified")
+	nodes, err := t.KubernetesClientset.CoreV1().Nodes().List(ctx, k8smetav1.ListOptions{})
+	if err != nil {
+		zap.L().Debug("returing addKubeNodesNet with error(s)")
+		return err
 	}
 
-	if len(t.CidrBlocks) <= 0 {
-		zap.L().Debug("returing addKubeAPINet with error(s)")
-		return fmt.Errorf("cluster has no CIDR blocks specified")
+	// The list is rebuilt every run so the external network follows the nodes as they scale
+	entries := make(map[string]bool)
+
+	for _, node := range nodes.Items {
+		for _, address := range node.Status.Addresses {
+			if address.Type == k8scorev1.NodeInternalIP || address.Type == k8scorev1.NodeExternalIP {
+				entries[address.Address] = true
+			}
+		}
+	}
+
+	for _, cidrBlock := range t.NodeCidrBlocks {
+		entries[cidrBlock] = true
+	}
+
+	if len(entries) == 0 {
+		zap.L().Debug(fmt.Sprintf("returing addKubeNodesNet; cluster %s has no nodes", t.name))
+		return nil
 	}
 
+	var sorted []string
+	for entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Strings(sorted)
+
+	zap.L().Debug(fmt.Sprintf("Creating external network for kube nodes (%d entries) for cluster %s", len(sorted), t.name))
 	t.prismaAPIConfig.AddExternalnetwork(
-		prisma_types.NewExternalnetwork("cluster networks").
+		prisma_types.NewExternalnetwork("Kube Nodes").
 			SetDescription("auto-generated by Cloud Operator").
-			SetProtected(t.protectConfig).SetEntries(t.CidrBlocks))
+			SetProtected(t.protectConfig).
+			SetPropagate(true).
+			SetEntries(sorted))
 
-	zap.L().Debug("returing addKubeAPINet")
+	zap.L().Debug("returing addKubeNodesNet")
+	return nil
+}
+
+// addKubeWorkloadAuth grants the workload role to the cloud identities mapped to the
+// ServiceAccounts selected by KubeWorkloadAuthPatterns with IRSA or GKE Workload Identity
+// annotations in the cluster namespace. ServiceAccounts with a bad annotation are skipped.
+func (t *KubeProcessor) addKubeWorkloadAuth(ctx context.Context) error {
+
+	zap.L().Debug("entering addKubeWorkloadAuth")
+
+	if !t.cloudOperatorConfig.HasOp(types.OpKubeWorkloadAuth) {
+		zap.L().Debug("returing addKubeWorkloadAuth option is disabled")
+		return nil
+	}
+
+	if t.workloadIdentity == nil {
+		zap.L().Warn(fmt.Sprintf("%s operation is not supported for cluster %s", types.OpKubeWorkloadAuth, t.name))
+		zap.L().Debug("returing addKubeWorkloadAuth; workload identity is not supported")
+		return nil
+	}
+
+	if t.KubernetesClientset == nil {
+		zap.L().Debug("returing addKubeWorkloadAuth with error(s)")
+		return fmt.Errorf("kubernetesClientset is required")
+	}
+
+	t.initPrismaAPIConfig()
+
+	patterns := t.cloudOperatorConfig.KubeWorkloadAuthPatterns
+
+	if len(patterns.Include) == 0 {
+		zap.L().Warn(fmt.Sprintf("%s operation has no include patterns; no ServiceAccount is authorized for cluster %s", types.OpKubeWorkloadAuth, t.name))
+		zap.L().Debug("returing addKubeWorkloadAuth; no include patterns")
+		return nil
+	}
+
+	err := patterns.Validate()
+	if err != nil {
+		zap.L().Debug("returing addKubeWorkloadAuth with error(s)")
+		return err
+	}
+
+	serviceAccounts, err := t.KubernetesClientset.CoreV1().ServiceAccounts("").List(ctx, k8smetav1.ListOptions{})
+	if err != nil {
+		zap.L().Debug("returing addKubeWorkloadAuth with error(s)")
+		return err
+	}
+
+	authBuilder := auth.NewBuilder(t.cloudOperatorConfig.Ops).
+		SetProtected(t.protectConfig).
+		SetWorkloadRole(t.cloudOperatorConfig.GetKubeWorkloadAuthRole())
+
+	for _, serviceAccount := range serviceAccounts.Items {
+
+		name := serviceAccount.Namespace + "/" + serviceAccount.Name
+
+		match, _ := patterns.Match(name)
+		if !match {
+			continue
+		}
+
+		// A bad annotation is the workload owner's problem and must not block the cluster
+		identity, err := t.workloadIdentity(serviceAccount.Annotations)
+		if err != nil {
+			zap.L().Warn(fmt.Sprintf("ServiceAccount %s in cluster %s skipped: %s", name, t.name, err))
+			continue
+		}
+
+		if identity == nil {
+			continue
+		}
+
+		zap.L().Debug(fmt.Sprintf("ServiceAccount %s mapped to %s in cluster %s", name, identity.Name, t.name))
+
+		authBuilder.AddWorkload(t.name, name, identity, t.prismaClient.GetNamespacePath())
+	}
+
+	authBuilder.AddTo(t.prismaAPIConfig)
+
+	zap.L().Debug("returing addKubeWorkloadAuth")
 	return nil
 }
 
@@ -255,9 +506,10 @@ func (t *KubeProcessor) installEnforcer(ctx context.Context) error {
 		return nil
 	}
 
-	if t.KubernetesClientset == nil {
-		zap.L().Debug("returing installEnforcer with error(s)")
-		return fmt.Errorf("kubernetesClientset is required")
+	if t.skipEnforcer != "" {
+		zap.L().Info(fmt.Sprintf("Not installing enforcer on cluster %s: %s", t.name, t.skipEnforcer))
+		zap.L().Debug("returing installEnforcer; enforcer is skipped")
+		return nil
 	}
 
 	if t.KubernetesDaemonsetBuilder == nil {
@@ -267,46 +519,125 @@ func (t *KubeProcessor) installEnforcer(ctx context.Context) error {
 
 	daemonset := t.KubernetesDaemonsetBuilder.Build()
 
-	zap.L().Debug(fmt.Sprintf("Applying Kubernetes namespace config to Cluster %s", t.name))
-	_, err := t.KubernetesClientset.CoreV1().Namespaces().Apply(ctx, daemonset.Namespace, k8smetav1.ApplyOptions{
-		FieldManager: "cloud-operator",
-	})
+	spec, err := t.cloudOperatorConfig.GetEnforcerSpec(t.name, t.tags)
 	if err != nil {
 		zap.L().Debug("returing installEnforcer with error(s)")
 		return err
 	}
 
-	zap.L().Debug(fmt.Sprintf("Applying Kubernetes Cluster Role config to Cluster %s", t.name))
-	_, err = t.KubernetesClientset.RbacV1().ClusterRoles().Apply(ctx, daemonset.ClusterRole, k8smetav1.ApplyOptions{
-		FieldManager: "cloud-operator",
-	})
+	daemonset.DaemonSet, err = overlayEnforcerSpec(daemonset.DaemonSet, spec)
 	if err != nil {
 		zap.L().Debug("returing installEnforcer with error(s)")
 		return err
 	}
 
-	zap.L().Debug(fmt.Sprintf("Applying Kubernetes Cluster Role Binding config to Cluster %s", t.name))
-	_, err = t.KubernetesClientset.RbacV1().ClusterRoleBindings().Apply(ctx, daemonset.ClusterRoleBinding, k8smetav1.ApplyOptions{
-		FieldManager: "cloud-operator",
-	})
+	// Clusters managed by GitOps receive the objects through the export directory only
+	export, err := t.cloudOperatorConfig.MatchKubeExport(t.name, t.tags)
 	if err != nil {
 		zap.L().Debug("returing installEnforcer with error(s)")
 		return err
 	}
 
-	zap.L().Debug(fmt.Sprintf("Applying Kubernetes Service Account config to Cluster %s", t.name))
-	_, err = t.KubernetesClientset.CoreV1().ServiceAccounts("aporeto").Apply(ctx, daemonset.ServiceAccount, k8smetav1.ApplyOptions{
-		FieldManager: "cloud-operator",
+	if export {
+		t.export, err = t.exportEnforcer(daemonset)
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+		zap.L().Debug("returing installEnforcer; objects were exported")
+		return nil
+	}
+
+	if t.KubernetesClientset == nil {
+		zap.L().Debug("returing installEnforcer with error(s)")
+		return fmt.Errorf("kubernetesClientset is required")
+	}
+
+	driftMode := t.cloudOperatorConfig.GetKubeDriftMode()
+
+	skip := make(map[string]bool)
+
+	if driftMode != types.DriftModeOff {
+
+		t.drift, err = t.detectDrift(ctx, t.managedObjects(daemonset))
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+
+		skip = skipApply(driftMode, t.drift)
+	}
+
+	if skip["Namespace"] {
+		zap.L().Debug(fmt.Sprintf("Skipping Kubernetes namespace config on Cluster %s (drift mode %s)", t.name, driftMode))
+	} else {
+		zap.L().Debug(fmt.Sprintf("Applying Kubernetes namespace config to Cluster %s", t.name))
+		_, err = t.KubernetesClientset.CoreV1().Namespaces().Apply(ctx, daemonset.Namespace, k8smetav1.ApplyOptions{
+			FieldManager: fieldManager,
+		})
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+	}
+
+	if skip["ClusterRole"] {
+		zap.L().Debug(fmt.Sprintf("Skipping Kubernetes Cluster Role config on Cluster %s (drift mode %s)", t.name, driftMode))
+	} else {
+		zap.L().Debug(fmt.Sprintf("Applying Kubernetes Cluster Role config to Cluster %s", t.name))
+		_, err = t.KubernetesClientset.RbacV1().ClusterRoles().Apply(ctx, daemonset.ClusterRole, k8smetav1.ApplyOptions{
+			FieldManager: fieldManager,
+		})
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+	}
+
+	if skip["ClusterRoleBinding"] {
+		zap.L().Debug(fmt.Sprintf("Skipping Kubernetes Cluster Role Binding config on Cluster %s (drift mode %s)", t.name, driftMode))
+	} else {
+		zap.L().Debug(fmt.Sprintf("Applying Kubernetes Cluster Role Binding config to Cluster %s", t.name))
+		_, err = t.KubernetesClientset.RbacV1().ClusterRoleBindings().Apply(ctx, daemonset.ClusterRoleBinding, k8smetav1.ApplyOptions{
+			FieldManager: fieldManager,
+		})
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+	}
+
+	if skip["ServiceAccount"] {
+		zap.L().Debug(fmt.Sprintf("Skipping Kubernetes Service Account config on Cluster %s (drift mode %s)", t.name, driftMode))
+	} else {
+		zap.L().Debug(fmt.Sprintf("Applying Kubernetes Service Account config to Cluster %s", t.name))
+		_, err = t.KubernetesClientset.CoreV1().ServiceAccounts("aporeto").Apply(ctx, daemonset.ServiceAccount, k8smetav1.ApplyOptions{
+			FieldManager: fieldManager,
+		})
+		if err != nil {
+			zap.L().Debug("returing installEnforcer with error(s)")
+			return err
+		}
+	}
+
+	if skip["DaemonSet"] {
+		zap.L().Debug(fmt.Sprintf("Skipping Kubernetes Daemonset config on Cluster %s (drift mode %s)", t.name, driftMode))
+		zap.L().Debug("returing installEnforcer")
+		return nil
+	}
+
+	zap.L().Debug(fmt.Sprintf("Applying Kubernetes Daemonset config to Cluster %s", t.name))
+	applied, err := t.KubernetesClientset.AppsV1().DaemonSets("aporeto").Apply(ctx, daemonset.DaemonSet, k8smetav1.ApplyOptions{
+		FieldManager: fieldManager,
 	})
 	if err != nil {
 		zap.L().Debug("returing installEnforcer with error(s)")
 		return err
 	}
 
-	zap.L().Debug(fmt.Sprintf("Applying Kubernetes Daemonset config to Cluster %s", t.name))
-	_, err = t.KubernetesClientset.AppsV1().DaemonSets("aporeto").Apply(ctx, daemonset.DaemonSet, k8smetav1.ApplyOptions{
-		FieldManager: "cloud-operator",
-	})
+	// Apply returns as soon as the object is accepted. Wait (bounded) for the pods so a
+	// crash looping enforcer is reported rather than COMPLETED.
+	t.rollout, err = t.waitForRollout(ctx, applied)
 	if err != nil {
 		zap.L().Debug("returing installEnforcer with error(s)")
 		return err
@@ -315,3 +646,12 @@ func (t *KubeProcessor) installEnforcer(ctx context.Context) error {
 	zap.L().Debug("returing installEnforcer")
 	return nil
 }
+
+func appendNonEmpty(list []string, v ...string) []string {
+	for _, s := range v {
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
@@ -0,0 +1,222 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+
+	builder "github.com/aporeto-se/enforcerd-kube-builder"
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// managedObject is a Kubernetes object installed by installEnforcer
+type managedObject struct {
+	kind      string
+	group     string
+	resource  string
+	namespace string
+	name      string
+	desired   interface{}
+	get       func(ctx context.Context) (k8smetav1.Object, error)
+	delete    func(ctx context.Context) error
+}
+
+// Uninstall removes the enforcer objects installed by the operator and the Prisma config
+// imported for the cluster. It is used for clusters that are no longer in scope. Returns
+// true if anything was removed.
+func (t *KubeProcessor) Uninstall(ctx context.Context) (bool, error) {
+
+	zap.L().Debug("entering Uninstall")
+
+	removed, err := t.uninstallEnforcer(ctx)
+	if err != nil {
+		zap.L().Debug("returning Uninstall with error(s)")
+		return removed, err
+	}
+
+	// Importing an empty config under the import label removes everything previously
+	// imported under it
+	zap.L().Debug(fmt.Sprintf("Removing Prisma API config for %s", t.importLabel))
+	err = t.prismaClient.ImportPrismaConfig(ctx, prisma_types.NewPrismaConfig(t.importLabel))
+	if err != nil {
+		zap.L().Debug("returning Uninstall with error(s)")
+		return removed, err
+	}
+
+	zap.L().Debug("returning Uninstall")
+	return removed, nil
+}
+
+// uninstallEnforcer deletes the objects applied by installEnforcer in reverse order. Only
+// objects with fields managed by the operator are deleted. Returns true if anything was deleted.
+func (t *KubeProcessor) uninstallEnforcer(ctx context.Context) (bool, error) {
+
+	zap.L().Debug("entering uninstallEnforcer")
+
+	export, err := t.cloudOperatorConfig.MatchKubeExport(t.name, t.tags)
+	if err != nil {
+		zap.L().Debug("returing uninstallEnforcer with error(s)")
+		return false, err
+	}
+
+	// Removing the exported objects lets the GitOps controller prune them
+	if export {
+		removed, err := t.unexportEnforcer()
+		if err != nil {
+			zap.L().Debug("returing uninstallEnforcer with error(s)")
+			return false, err
+		}
+		zap.L().Debug("returing uninstallEnforcer")
+		return removed, nil
+	}
+
+	if t.KubernetesClientset == nil {
+		zap.L().Debug("returing uninstallEnforcer with error(s)")
+		return false, fmt.Errorf("kubernetesClientset is required")
+	}
+
+	if t.KubernetesDaemonsetBuilder == nil {
+		zap.L().Debug("returing uninstallEnforcer with error(s)")
+		return false, fmt.Errorf("kubernetesDaemonsetBuilder is required")
+	}
+
+	objects := t.managedObjects(t.KubernetesDaemonsetBuilder.Build())
+
+	removed := false
+
+	for _, object := range objects {
+
+		if object.name == "" {
+			continue
+		}
+
+		existing, err := object.get(ctx)
+		if k8serrors.IsNotFound(err) {
+			zap.L().Debug(fmt.Sprintf("%s %s not found on cluster %s", object.kind, object.name, t.name))
+			continue
+		}
+		if err != nil {
+			zap.L().Debug("returing uninstallEnforcer with error(s)")
+			return removed, err
+		}
+
+		if !isManaged(existing) {
+			zap.L().Debug(fmt.Sprintf("%s %s on cluster %s is not managed by %s; skipping", object.kind, object.name, t.name, fieldManager))
+			continue
+		}
+
+		zap.L().Debug(fmt.Sprintf("Deleting %s %s from cluster %s", object.kind, object.name, t.name))
+		err = object.delete(ctx)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			zap.L().Debug("returing uninstallEnforcer with error(s)")
+			return removed, err
+		}
+
+		removed = true
+	}
+
+	zap.L().Debug("returing uninstallEnforcer")
+	return removed, nil
+}
+
+// managedObjects returns the objects of daemonset applied by installEnforcer in reverse
+// order of installation
+func (t *KubeProcessor) managedObjects(daemonset *builder.Daemonset) []*managedObject {
+
+	namespace := "aporeto"
+	if daemonset.DaemonSet.Namespace != nil {
+		namespace = *daemonset.DaemonSet.Namespace
+	}
+
+	client := t.KubernetesClientset
+	deleteOptions := k8smetav1.DeleteOptions{}
+
+	return []*managedObject{
+		{
+			kind:      "DaemonSet",
+			group:     "apps",
+			resource:  "daemonsets",
+			namespace: namespace,
+			name:      stringValue(daemonset.DaemonSet.Name),
+			desired:   daemonset.DaemonSet,
+			get: func(ctx context.Context) (k8smetav1.Object, error) {
+				return client.AppsV1().DaemonSets(namespace).Get(ctx, stringValue(daemonset.DaemonSet.Name), k8smetav1.GetOptions{})
+			},
+			delete: func(ctx context.Context) error {
+				return client.AppsV1().DaemonSets(namespace).Delete(ctx, stringValue(daemonset.DaemonSet.Name), deleteOptions)
+			},
+		},
+		{
+			kind:      "ServiceAccount",
+			group:     "",
+			resource:  "serviceaccounts",
+			namespace: namespace,
+			name:      stringValue(daemonset.ServiceAccount.Name),
+			desired:   daemonset.ServiceAccount,
+			get: func(ctx context.Context) (k8smetav1.Object, error) {
+				return client.CoreV1().ServiceAccounts(namespace).Get(ctx, stringValue(daemonset.ServiceAccount.Name), k8smetav1.GetOptions{})
+			},
+			delete: func(ctx context.Context) error {
+				return client.CoreV1().ServiceAccounts(namespace).Delete(ctx, stringValue(daemonset.ServiceAccount.Name), deleteOptions)
+			},
+		},
+		{
+			kind:     "ClusterRoleBinding",
+			group:    "rbac.authorization.k8s.io",
+			resource: "clusterrolebindings",
+			name:     stringValue(daemonset.ClusterRoleBinding.Name),
+			desired:  daemonset.ClusterRoleBinding,
+			get: func(ctx context.Context) (k8smetav1.Object, error) {
+				return client.RbacV1().ClusterRoleBindings().Get(ctx, stringValue(daemonset.ClusterRoleBinding.Name), k8smetav1.GetOptions{})
+			},
+			delete: func(ctx context.Context) error {
+				return client.RbacV1().ClusterRoleBindings().Delete(ctx, stringValue(daemonset.ClusterRoleBinding.Name), deleteOptions)
+			},
+		},
+		{
+			kind:     "ClusterRole",
+			group:    "rbac.authorization.k8s.io",
+			resource: "clusterroles",
+			name:     stringValue(daemonset.ClusterRole.Name),
+			desired:  daemonset.ClusterRole,
+			get: func(ctx context.Context) (k8smetav1.Object, error) {
+				return client.RbacV1().ClusterRoles().Get(ctx, stringValue(daemonset.ClusterRole.Name), k8smetav1.GetOptions{})
+			},
+			delete: func(ctx context.Context) error {
+				return client.RbacV1().ClusterRoles().Delete(ctx, stringValue(daemonset.ClusterRole.Name), deleteOptions)
+			},
+		},
+		{
+			kind:     "Namespace",
+			group:    "",
+			resource: "namespaces",
+			name:     stringValue(daemonset.Namespace.Name),
+			desired:  daemonset.Namespace,
+			get: func(ctx context.Context) (k8smetav1.Object, error) {
+				return client.CoreV1().Namespaces().Get(ctx, stringValue(daemonset.Namespace.Name), k8smetav1.GetOptions{})
+			},
+			delete: func(ctx context.Context) error {
+				return client.CoreV1().Namespaces().Delete(ctx, stringValue(daemonset.Namespace.Name), deleteOptions)
+			},
+		},
+	}
+}
+
+// isManaged returns true if the operator manages any field of object
+func isManaged(object k8smetav1.Object) bool {
+	for _, entry := range object.GetManagedFields() {
+		if entry.Manager == fieldManager {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
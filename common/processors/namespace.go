@@ -3,20 +3,38 @@ package processors
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
 	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/cloud-operator/common/layout"
 	"github.com/aporeto-se/cloud-operator/common/types"
 )
 
 // NamespaceProcessor processor
 type NamespaceProcessor struct {
-	kube                []string
-	compute             []string
+	kube                []*types.NamespaceEntity
+	compute             []*types.NamespaceEntity
 	cloudOperatorConfig *types.CloudOperatorConfig
-	prismaClient        *prisma_api.Client
+	layout              layout.NamespaceLayout
+	clients             map[string]namespaceClient
+	created             map[string]bool
+	deleteOverride      bool
+}
+
+// namespaceRef is a namespace found below the cloud account namespace along with the
+// path of its parent relative to the cloud account namespace. Descendants is the number
+// of namespaces below it that are deleted with it.
+type namespaceRef struct {
+	namespace   *prisma_types.Namespace
+	ptype       types.CloudEntityType
+	parent      string
+	path        string
+	descendants int
 }
 
 // NewNamespaceProcessor returns new entity instance
@@ -30,39 +48,178 @@ func NewNamespaceProcessor(cloudOperatorConfig *types.CloudOperatorConfig, prism
 		return nil, fmt.Errorf("entity PrismaClient is required")
 	}
 
+	var errors *multierror.Error
+
+	err := cloudOperatorConfig.NamespaceCreatePatterns.Validate()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = cloudOperatorConfig.NamespaceDeletePatterns.Validate()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	namespaceLayout, err := layout.NewNamespaceLayout(cloudOperatorConfig)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = errors.ErrorOrNil()
+	if err != nil {
+		return nil, err
+	}
+
 	return &NamespaceProcessor{
 		cloudOperatorConfig: cloudOperatorConfig,
-		prismaClient:        prismaClient,
+		layout:              namespaceLayout,
+		clients:             map[string]namespaceClient{"": &prismaNamespaceClient{client: prismaClient}},
+		created:             make(map[string]bool),
 	}, nil
 }
 
-func (t *NamespaceProcessor) has(v string) bool {
+// has returns true if the namespace at path (relative to the cloud account namespace) belongs
+// to an entity or is an intermediate namespace of an entity
+func (t *NamespaceProcessor) has(path string) bool {
 
-	for _, namespace := range t.kube {
-		if namespace == v {
+	for _, entity := range append(append([]*types.NamespaceEntity{}, t.kube...), t.compute...) {
+		entityPath := t.layout.Path(entity)
+		if entityPath == path || strings.HasPrefix(entityPath, path+"/") {
 			return true
 		}
 	}
 
-	for _, namespace := range t.compute {
-		if namespace == v {
-			return true
+	return false
+}
+
+// legacy returns the entity whose namespace was at path before every name was sanitized or nil
+func (t *NamespaceProcessor) legacy(path string) *types.NamespaceEntity {
+
+	for _, entity := range append(append([]*types.NamespaceEntity{}, t.kube...), t.compute...) {
+		if t.layout.LegacyPath(entity) == path {
+			return entity
 		}
 	}
 
-	return false
+	return nil
 }
 
 // AddKube ...
-func (t *NamespaceProcessor) AddKube(v ...string) {
+func (t *NamespaceProcessor) AddKube(v ...*types.NamespaceEntity) {
 	t.kube = append(t.kube, v...)
 }
 
 // AddCompute ...
-func (t *NamespaceProcessor) AddCompute(v ...string) {
+func (t *NamespaceProcessor) AddCompute(v ...*types.NamespaceEntity) {
 	t.compute = append(t.compute, v...)
 }
 
+// SetDeleteOverride disables the delete maximum count and percentage checks. It is set per
+// run from the run input and never from the config.
+func (t *NamespaceProcessor) SetDeleteOverride(v bool) {
+	t.deleteOverride = v
+}
+
+// client returns the Prisma client for the namespace at path relative to the cloud account
+// namespace. Clients are cached for the life of the processor.
+func (t *NamespaceProcessor) client(ctx context.Context, path string) (namespaceClient, error) {
+
+	if client, ok := t.clients[path]; ok {
+		return client, nil
+	}
+
+	parentPath, name := splitPath(path)
+
+	parent, err := t.client(ctx, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := parent.child(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	t.clients[path] = client
+	return client, nil
+}
+
+// walk returns the namespaces below the namespace at parent. Intermediate (layout) namespaces
+// are always descended into so that their children are managed individually.
+func (t *NamespaceProcessor) walk(ctx context.Context, parent string, report *types.NamespaceReports) []*namespaceRef {
+
+	var result []*namespaceRef
+
+	client, err := t.client(ctx, parent)
+	if err != nil {
+		report.AddNamespaces(types.NewNamespaceReport(parent).
+			SetOperation(types.NamespaceOperationDelete).
+			SetStatus(types.OperationStatusFailed).
+			SetError(err))
+		return nil
+	}
+
+	for _, namespace := range client.namespaces() {
+
+		path := joinPath(parent, namespace.Name)
+
+		namespaceType, err := t.namespaceType(namespace)
+
+		if err != nil {
+			report.AddNamespaces(types.NewNamespaceReport(path).
+				SetOperation(types.NamespaceOperationDelete).
+				SetStatus(types.OperationStatusFailed))
+			continue
+		}
+
+		result = append(result, &namespaceRef{
+			namespace: namespace,
+			ptype:     namespaceType,
+			parent:    parent,
+			path:      path,
+		})
+
+		if namespaceType == types.CloudEntityTypeLayout {
+			result = append(result, t.walk(ctx, path, report)...)
+		}
+	}
+
+	return result
+}
+
+// descendants returns the number of namespaces below the namespace at path and the paths
+// of those excluded from deletion by the delete patterns
+func (t *NamespaceProcessor) descendants(ctx context.Context, path string) (int, []string, error) {
+
+	client, err := t.client(ctx, path)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	count := 0
+	var excluded []string
+
+	for _, namespace := range client.namespaces() {
+
+		childPath := joinPath(path, namespace.Name)
+
+		match, _ := t.cloudOperatorConfig.NamespaceDeletePatterns.Match(namespace.Name)
+		if !match {
+			excluded = append(excluded, childPath)
+		}
+
+		n, childExcluded, err := t.descendants(ctx, childPath)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		count += 1 + n
+		excluded = append(excluded, childExcluded...)
+	}
+
+	return count, excluded, nil
+}
+
 // CloudEntityTypeInvalid CloudEntityType = "INVALID"
 // CloudEntityTypeCompute CloudEntityType = "COMPUTE"
 // CloudEntityTypeKubernetes CloudEntityType = "KUBERNETES"
@@ -79,6 +236,7 @@ func (t *NamespaceProcessor) Process(ctx context.Context) *types.NamespaceReport
 	rogueNamespaceDeleteEnabled := false
 	computeNamespaceDeleteEnabled := false
 	kubeNamespaceDeleteEnabled := false
+	migrateNamesEnabled := false
 
 	if t.cloudOperatorConfig.HasOp(types.OpNamespaceRogueDelete) {
 		zap.L().Debug(fmt.Sprintf("Op %s is enabled", types.OpNamespaceComputeDelete))
@@ -104,69 +262,157 @@ func (t *NamespaceProcessor) Process(ctx context.Context) *types.NamespaceReport
 		zap.L().Debug(fmt.Sprintf("Op %s is disabled", types.OpNamespaceKubeDelete))
 	}
 
+	if t.cloudOperatorConfig.HasOp(types.OpNamespaceMigrateNames) {
+		zap.L().Debug(fmt.Sprintf("Op %s is enabled", types.OpNamespaceMigrateNames))
+		namespaceDeleteEnabled = true
+		migrateNamesEnabled = true
+	} else {
+		zap.L().Debug(fmt.Sprintf("Op %s is disabled", types.OpNamespaceMigrateNames))
+	}
+
 	if namespaceDeleteEnabled {
 
-		for _, namespace := range t.prismaClient.GetNamespaces() {
+		// Deletion is done in two phases. The first run a namespace is found unused it is marked
+		// (tombstoned). It is only deleted once it has been unused for the grace period or the
+		// grace runs. If the entity returns before then the mark is removed.
+		now := time.Now()
 
-			namespaceType, err := t.namespaceType(namespace)
+		namespaces := t.walk(ctx, "", report)
 
-			if err != nil {
-				report.AddNamespaces(types.NewNamespaceReport(namespace.Name).
-					SetOperation(types.NamespaceOperationDelete).
-					SetStatus(types.OperationStatusFailed))
-				continue
-			}
+		// Layout namespaces are only deleted once they have no children
+		children := make(map[string]int)
+		for _, ref := range namespaces {
+			children[ref.parent]++
+		}
+
+		var retire []*namespaceRef
 
-			switch namespaceType {
+		for _, ref := range namespaces {
+
+			deleteEnabled := false
+
+			switch ref.ptype {
 
 			case types.CloudEntityTypeDefault:
+				deleteEnabled = rogueNamespaceDeleteEnabled
+
+			case types.CloudEntityTypeCompute:
+				deleteEnabled = computeNamespaceDeleteEnabled
 
-				if t.has(namespace.Name) {
-					zap.L().Debug(fmt.Sprintf("namespace %s type %s is in use", namespace.Name, namespaceType))
-				} else {
+			case types.CloudEntityTypeKubernetes:
+				deleteEnabled = kubeNamespaceDeleteEnabled
+
+			case types.CloudEntityTypeLayout:
+				deleteEnabled = computeNamespaceDeleteEnabled || kubeNamespaceDeleteEnabled
+
+			}
 
-					zap.L().Debug(fmt.Sprintf("namespace %s type %s is in not in use", namespace.Name, namespaceType))
+			if t.has(ref.path) {
 
-					if rogueNamespaceDeleteEnabled {
-						report.AddNamespaces(t.namespaceDeleteReport(ctx, namespace, namespaceType))
-					}
+				zap.L().Debug(fmt.Sprintf("namespace %s type %s is in use", ref.path, ref.ptype))
+
+				if hasTombstone(ref.namespace) {
+					report.AddNamespaces(t.namespaceRestoreReport(ctx, ref))
 				}
 
-			case types.CloudEntityTypeCompute:
+				continue
+			}
 
-				if t.has(namespace.Name) {
-					zap.L().Debug(fmt.Sprintf("namespace %s type %s is in use", namespace.Name, namespaceType))
-				} else {
+			zap.L().Debug(fmt.Sprintf("namespace %s type %s is in not in use", ref.path, ref.ptype))
 
-					zap.L().Debug(fmt.Sprintf("namespace %s type %s is in not in use", namespace.Name, namespaceType))
+			// A namespace named before every name was sanitized is kept alongside the new
+			// namespace of its entity until the names are explicitly migrated
+			if entity := t.legacy(ref.path); entity != nil {
 
-					if computeNamespaceDeleteEnabled {
-						report.AddNamespaces(t.namespaceDeleteReport(ctx, namespace, namespaceType))
-					}
+				if !migrateNamesEnabled {
+					report.AddNamespaces(t.namespaceLegacyReport(ref, entity))
+					continue
 				}
 
-			case types.CloudEntityTypeKubernetes:
+				zap.L().Debug(fmt.Sprintf("namespace %s has the legacy name of %s %s and is migrated", ref.path, entity.Type, entity.Name))
+				deleteEnabled = true
+			}
+
+			if !deleteEnabled {
+				continue
+			}
+
+			// Namespaces not matching the delete patterns are not managed by the operator
+			// and are left alone
+			match, _ := t.cloudOperatorConfig.NamespaceDeletePatterns.Match(ref.namespace.Name)
+			if !match {
+				zap.L().Debug(fmt.Sprintf("namespace %s is excluded from deletion by pattern", ref.path))
+				report.AddNamespaces(t.namespaceIgnoreReport(ref.path, ref.ptype, types.NamespaceOperationDelete))
+				continue
+			}
 
-				if t.has(namespace.Name) {
-					zap.L().Debug(fmt.Sprintf("compute namespace %s is active not be deleted", namespace.Name))
-				} else {
-					if kubeNamespaceDeleteEnabled {
-						report.AddNamespaces(t.namespaceDeleteReport(ctx, namespace, namespaceType))
-					}
+			if ref.ptype == types.CloudEntityTypeLayout {
+				if children[ref.path] > 0 {
+					zap.L().Debug(fmt.Sprintf("layout namespace %s still has children", ref.path))
+					continue
+				}
+			} else {
+				// Prisma deletes the children along with the namespace so they count toward
+				// the blast radius
+				descendants, excluded, err := t.descendants(ctx, ref.path)
+				if err != nil {
+					report.AddNamespaces(types.NewNamespaceReport(ref.path).
+						SetOperation(types.NamespaceOperationDelete).
+						SetStatus(types.OperationStatusFailed).
+						SetType(ref.ptype).
+						SetError(err))
+					continue
+				}
+
+				if len(excluded) > 0 {
+					zap.L().Debug(fmt.Sprintf("namespace %s has children excluded from deletion by pattern: %s", ref.path, strings.Join(excluded, ", ")))
+					report.AddNamespaces(t.namespaceIgnoreReport(ref.path, ref.ptype, types.NamespaceOperationDelete))
+					continue
 				}
 
+				ref.descendants = descendants
 			}
 
+			retire = append(retire, ref)
+
+		}
+
+		count := 0
+		total := len(namespaces)
+		for _, ref := range retire {
+			count += 1 + ref.descendants
+			total += ref.descendants
+		}
+
+		// If the cloud API returns an empty or partial inventory (wrong region, expired credentials,
+		// missing permission) every namespace would appear unused. We refuse to touch any of them
+		// if the blast radius is larger than configured unless explicitly overridden.
+		err := t.checkBlastRadius(count, total)
+		if err != nil {
+			zap.L().Warn(err.Error())
+			report.SetError(err)
+		} else {
+			for _, ref := range retire {
+				report.AddNamespaces(t.namespaceRetireReport(ctx, ref, now))
+			}
 		}
 
 	}
 
+	// Entities that map to the same namespace are reported and not created. Otherwise one
+	// entity would silently take over the namespace (and policies) of the other.
+	conflicts := t.conflicts()
+
 	if t.cloudOperatorConfig.HasOp(types.OpNamespaceComputeCreate) {
 
 		zap.L().Debug(fmt.Sprintf("Op %s is enabled", types.OpNamespaceComputeCreate))
 
-		for _, namespace := range t.compute {
-			report.AddNamespaces(t.namespaceCreateReport(ctx, namespace, types.CloudEntityTypeCompute))
+		for _, entity := range t.compute {
+			if err, ok := conflicts[entity]; ok {
+				report.AddNamespaces(t.namespaceConflictReport(entity, err))
+				continue
+			}
+			report.AddNamespaces(t.namespaceCreateReport(ctx, entity))
 		}
 	} else {
 		zap.L().Debug(fmt.Sprintf("Op %s is disabled", types.OpNamespaceComputeCreate))
@@ -176,8 +422,12 @@ func (t *NamespaceProcessor) Process(ctx context.Context) *types.NamespaceReport
 
 		zap.L().Debug(fmt.Sprintf("Op %s is enabled", types.OpNamespaceKubeCreate))
 
-		for _, namespace := range t.kube {
-			report.AddNamespaces(t.namespaceCreateReport(ctx, namespace, types.CloudEntityTypeKubernetes))
+		for _, entity := range t.kube {
+			if err, ok := conflicts[entity]; ok {
+				report.AddNamespaces(t.namespaceConflictReport(entity, err))
+				continue
+			}
+			report.AddNamespaces(t.namespaceCreateReport(ctx, entity))
 		}
 
 	} else {
@@ -188,31 +438,93 @@ func (t *NamespaceProcessor) Process(ctx context.Context) *types.NamespaceReport
 	return report
 }
 
-func (t *NamespaceProcessor) namespaceCreateReport(ctx context.Context, name string, ptype types.CloudEntityType) *types.NamespaceReport {
+// conflicts returns an error for each entity whose namespace collides with the namespace of
+// another entity
+func (t *NamespaceProcessor) conflicts() map[*types.NamespaceEntity]error {
+
+	result := make(map[*types.NamespaceEntity]error)
+
+	entities := append(append([]*types.NamespaceEntity{}, t.compute...), t.kube...)
+
+	// Identical entities share the path of the conflicting entity and are skipped as well
+	conflicts := layout.Conflicts(t.layout, entities...)
+
+	for _, entity := range entities {
+		if err, ok := conflicts[t.layout.Path(entity)]; ok {
+			result[entity] = err
+		}
+	}
+
+	return result
+}
+
+func (t *NamespaceProcessor) namespaceConflictReport(entity *types.NamespaceEntity, err error) *types.NamespaceReport {
+	return types.NewNamespaceReport(t.layout.Path(entity)).
+		SetOperation(types.NamespaceOperationCreate).
+		SetStatus(types.OperationStatusFailed).
+		SetType(entity.Type).
+		SetReason("namespace conflict").
+		SetError(err)
+}
+
+func (t *NamespaceProcessor) namespaceCreateReport(ctx context.Context, entity *types.NamespaceEntity) *types.NamespaceReport {
 
 	zap.L().Debug("entering namespaceCreateReport")
 
+	path := t.layout.Path(entity)
+	ptype := entity.Type
+
 	// Set status to failed. This is so we an bail on error. If and when the status
 	// changes we will update it. We also set the type to Compute. We will update if
 	// necessary.
-	report := types.NewNamespaceReport(name).
+	report := types.NewNamespaceReport(path).
 		SetOperation(types.NamespaceOperationCreate).
 		SetStatus(types.OperationStatusFailed).
 		SetType(ptype)
 
-	if t.prismaClient.HasNamespace(name) {
-		zap.L().Debug(fmt.Sprintf("namespace %s already exist", name))
+	parent, name := splitPath(path)
+
+	match, _ := t.cloudOperatorConfig.NamespaceCreatePatterns.Match(name)
+	if !match {
+		zap.L().Debug(fmt.Sprintf("namespace %s is excluded from creation by pattern", path))
+		return t.namespaceIgnoreReport(path, ptype, types.NamespaceOperationCreate)
+	}
+
+	annotations, err := tagAnnotations(t.cloudOperatorConfig, entity)
+	if err != nil {
+		zap.L().Debug("returning namespaceCreateReport with error(s)")
+		return report.SetError(err)
+	}
+
+	// The intermediate namespaces of the layout are created as needed
+	created := ""
+	for _, segment := range layout.Segments(parent) {
+		err := t.createNamespace(ctx, created, segment, types.CloudEntityTypeLayout, nil)
+		if err != nil {
+			zap.L().Debug("returning namespaceCreateReport with error(s)")
+			return report.SetError(err)
+		}
+		created = joinPath(created, segment)
+	}
+
+	client, err := t.client(ctx, parent)
+	if err != nil {
+		zap.L().Debug("returning namespaceCreateReport with error(s)")
+		return report.SetError(err)
+	}
+
+	if t.created[path] {
+		zap.L().Debug(fmt.Sprintf("namespace %s already exist", path))
 		return report.SetStatus(types.OperationStatusAlreadyExist)
 	}
 
-	zap.L().Debug(fmt.Sprintf("namespace %s does not exist; creating", name))
-	_, err := t.prismaClient.CreateNamespace(ctx,
-		prisma_types.NewNamespace(name).
-			SetNamespaceType(prisma_types.NamespaceTypeGroup).
-			AddAnnotation(namespaceAnnotationKey, []string{string(ptype)}).
-			SetDefaultPUIncomingTrafficAction(prisma_types.TrafficActionInherit).
-			SetDefaultPUOutgoingTrafficAction(prisma_types.TrafficActionInherit))
+	if client.has(name) {
+		zap.L().Debug(fmt.Sprintf("namespace %s already exist", path))
+		return t.namespaceUpdateReport(ctx, client, report, name, annotations)
+	}
 
+	zap.L().Debug(fmt.Sprintf("namespace %s does not exist; creating", path))
+	err = t.createNamespace(ctx, parent, name, ptype, annotations)
 	if err != nil {
 		zap.L().Debug("returning namespaceCreateReport with error(s)")
 		return report.SetError(err)
@@ -222,20 +534,253 @@ func (t *NamespaceProcessor) namespaceCreateReport(ctx context.Context, name str
 	return report.SetStatus(types.OperationStatusCompleted)
 }
 
-func (t *NamespaceProcessor) namespaceDeleteReport(ctx context.Context, namespace *prisma_types.Namespace, ptype types.CloudEntityType) *types.NamespaceReport {
+// namespaceUpdateReport keeps the tag annotations of the existing namespace name up to date. If
+// tags are not synced or nothing changed report is returned with status AlreadyExist.
+func (t *NamespaceProcessor) namespaceUpdateReport(ctx context.Context, client namespaceClient, report *types.NamespaceReport, name string, annotations map[string][]string) *types.NamespaceReport {
+
+	zap.L().Debug("entering namespaceUpdateReport")
+
+	if len(t.cloudOperatorConfig.NamespaceTagKeys) == 0 {
+		zap.L().Debug("returning namespaceUpdateReport (tag sync disabled)")
+		return report.SetStatus(types.OperationStatusAlreadyExist)
+	}
+
+	var namespace *prisma_types.Namespace
+	for _, v := range client.namespaces() {
+		if v.Name == name {
+			namespace = v
+			break
+		}
+	}
+
+	if namespace == nil || !syncTagAnnotations(namespace, t.cloudOperatorConfig.GetNamespaceTagPrefix(), annotations) {
+		zap.L().Debug("returning namespaceUpdateReport (no change)")
+		return report.SetStatus(types.OperationStatusAlreadyExist)
+	}
+
+	report.SetOperation(types.NamespaceOperationUpdate)
+
+	zap.L().Debug(fmt.Sprintf("updating tag annotations of namespace %s", name))
+	err := client.update(ctx, namespace)
+	if err != nil {
+		zap.L().Debug("returning namespaceUpdateReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning namespaceUpdateReport")
+	return report.SetStatus(types.OperationStatusCompleted).SetReason("tags updated")
+}
+
+// createNamespace creates the namespace name below the namespace at parent if it does not exist
+func (t *NamespaceProcessor) createNamespace(ctx context.Context, parent, name string, ptype types.CloudEntityType, annotations map[string][]string) error {
+
+	path := joinPath(parent, name)
+
+	if t.created[path] {
+		return nil
+	}
+
+	client, err := t.client(ctx, parent)
+	if err != nil {
+		return err
+	}
+
+	if client.has(name) {
+		return nil
+	}
+
+	namespace := prisma_types.NewNamespace(name).
+		SetNamespaceType(prisma_types.NamespaceTypeGroup).
+		AddAnnotation(namespaceAnnotationKey, []string{string(ptype)}).
+		SetDefaultPUIncomingTrafficAction(prisma_types.TrafficActionInherit).
+		SetDefaultPUOutgoingTrafficAction(prisma_types.TrafficActionInherit)
+
+	for key, values := range annotations {
+		namespace.AddAnnotation(key, values)
+	}
+
+	err = client.create(ctx, namespace)
+
+	if err != nil {
+		return err
+	}
+
+	t.created[path] = true
+	return nil
+}
+
+// checkBlastRadius returns an error if deleting count of total namespaces exceeds the configured
+// maximum count or percentage and the override is not set for this run. Both include the children
+// deleted along with a namespace.
+func (t *NamespaceProcessor) checkBlastRadius(count, total int) error {
+
+	if count <= 0 {
+		return nil
+	}
+
+	if t.deleteOverride {
+		zap.L().Debug(fmt.Sprintf("namespace delete override is set for this run; %d of %d namespaces may be deleted", count, total))
+		return nil
+	}
+
+	maxCount := t.cloudOperatorConfig.GetNamespaceDeleteMaxCount()
+	if maxCount > 0 && count > maxCount {
+		return fmt.Errorf("namespace deletion aborted: %d namespaces including children would be deleted which exceeds the maximum count of %d (env var %s); run once with the namespace delete override to proceed",
+			count, maxCount, types.NamespaceDeleteMaxCountEnv)
+	}
+
+	// count can not exceed total but guard the division anyway
+	if total < count {
+		total = count
+	}
+
+	maxPercent := t.cloudOperatorConfig.GetNamespaceDeleteMaxPercent()
+	percent := count * 100 / total
+	if maxPercent > 0 && percent > maxPercent {
+		return fmt.Errorf("namespace deletion aborted: %d of %d namespaces (%d%%) including children would be deleted which exceeds the maximum of %d%% (env var %s); run once with the namespace delete override to proceed",
+			count, total, percent, maxPercent, types.NamespaceDeleteMaxPercentEnv)
+	}
+
+	return nil
+}
+
+func (t *NamespaceProcessor) namespaceRetireReport(ctx context.Context, ref *namespaceRef, now time.Time) *types.NamespaceReport {
+
+	zap.L().Debug("entering namespaceRetireReport")
+
+	namespace := ref.namespace
+
+	report := types.NewNamespaceReport(ref.path).
+		SetOperation(types.NamespaceOperationTombstone).
+		SetStatus(types.OperationStatusFailed).
+		SetType(ref.ptype)
+
+	client, err := t.client(ctx, ref.parent)
+	if err != nil {
+		zap.L().Debug("returning namespaceRetireReport with error(s)")
+		return report.SetError(err)
+	}
+
+	mark, err := getTombstone(namespace)
+	if err != nil {
+		// An invalid mark is replaced with a new one. This restarts the grace period which is
+		// the safe option.
+		zap.L().Warn(err.Error())
+		mark = nil
+	}
+
+	if mark == nil {
+
+		mark = &tombstone{
+			time: now,
+			runs: 1,
+		}
+
+		report.SetTombstone(mark.time, mark.runs)
+
+		zap.L().Debug(fmt.Sprintf("tombstoning namespace %s", ref.path))
+		setTombstone(namespace, mark)
+		err = client.update(ctx, namespace)
+		if err != nil {
+			zap.L().Debug("returning namespaceRetireReport with error(s)")
+			return report.SetError(err)
+		}
+
+		zap.L().Debug("returning namespaceRetireReport")
+		return report.SetStatus(types.OperationStatusCompleted)
+	}
+
+	mark.runs++
+
+	if mark.expired(now, t.cloudOperatorConfig.GetNamespaceDeleteGracePeriod(), t.cloudOperatorConfig.GetNamespaceDeleteGraceRuns()) {
+		zap.L().Debug(fmt.Sprintf("namespace %s tombstone expired", ref.path))
+		zap.L().Debug("returning namespaceRetireReport")
+		return t.namespaceDeleteReport(ctx, ref).SetTombstone(mark.time, mark.runs)
+	}
+
+	report.SetTombstone(mark.time, mark.runs)
+
+	zap.L().Debug(fmt.Sprintf("namespace %s tombstone not expired (runs %d)", ref.path, mark.runs))
+	setTombstone(namespace, mark)
+	err = client.update(ctx, namespace)
+	if err != nil {
+		zap.L().Debug("returning namespaceRetireReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning namespaceRetireReport")
+	return report.SetStatus(types.OperationStatusPending)
+}
+
+func (t *NamespaceProcessor) namespaceRestoreReport(ctx context.Context, ref *namespaceRef) *types.NamespaceReport {
+
+	zap.L().Debug("entering namespaceRestoreReport")
+
+	report := types.NewNamespaceReport(ref.path).
+		SetOperation(types.NamespaceOperationRestore).
+		SetStatus(types.OperationStatusFailed).
+		SetType(ref.ptype)
+
+	client, err := t.client(ctx, ref.parent)
+	if err != nil {
+		zap.L().Debug("returning namespaceRestoreReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug(fmt.Sprintf("removing tombstone from namespace %s", ref.path))
+	clearTombstone(ref.namespace)
+	err = client.update(ctx, ref.namespace)
+	if err != nil {
+		zap.L().Debug("returning namespaceRestoreReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning namespaceRestoreReport")
+	return report.SetStatus(types.OperationStatusCompleted)
+}
+
+// namespaceIgnoreReport returns a report for a namespace that was skipped by the
+// include/exclude patterns of the operation
+func (t *NamespaceProcessor) namespaceIgnoreReport(name string, ptype types.CloudEntityType, operation types.NamespaceOperation) *types.NamespaceReport {
+	return types.NewNamespaceReport(name).
+		SetOperation(types.NamespaceOperationIgnore).
+		SetStatus(types.OperationStatusSkipped).
+		SetType(ptype).
+		SetReason(fmt.Sprintf("%s excluded by pattern", operation))
+}
+
+func (t *NamespaceProcessor) namespaceLegacyReport(ref *namespaceRef, entity *types.NamespaceEntity) *types.NamespaceReport {
+
+	zap.L().Warn(fmt.Sprintf("namespace %s has the legacy name of %s %s whose namespace is now %s; enable op %s to retire it",
+		ref.path, entity.Type, entity.Name, t.layout.Path(entity), types.OpNamespaceMigrateNames))
+
+	return types.NewNamespaceReport(ref.path).
+		SetOperation(types.NamespaceOperationIgnore).
+		SetStatus(types.OperationStatusSkipped).
+		SetType(ref.ptype).
+		SetReason(fmt.Sprintf("legacy name of %s; op %s is disabled", t.layout.Path(entity), types.OpNamespaceMigrateNames))
+}
+
+func (t *NamespaceProcessor) namespaceDeleteReport(ctx context.Context, ref *namespaceRef) *types.NamespaceReport {
 
 	zap.L().Debug("entering namespaceDeleteReport")
 
 	// Set status to failed. This is so we an bail on error. If and when the status
 	// changes we will update it. We also set the type to Compute. We will update if
 	// necessary.
-	report := types.NewNamespaceReport(namespace.Name).
+	report := types.NewNamespaceReport(ref.path).
 		SetOperation(types.NamespaceOperationDelete).
 		SetStatus(types.OperationStatusFailed).
-		SetType(ptype)
+		SetType(ref.ptype)
+
+	client, err := t.client(ctx, ref.parent)
+	if err != nil {
+		zap.L().Debug("returning namespaceDeleteReport with error(s)")
+		return report.SetError(err)
+	}
 
-	zap.L().Debug(fmt.Sprintf("deleting namespace %s", namespace.Name))
-	err := t.prismaClient.DeleteNamespace(ctx, namespace.Name)
+	zap.L().Debug(fmt.Sprintf("deleting namespace %s", ref.path))
+	err = client.remove(ctx, ref.namespace.Name)
 
 	if err != nil {
 		zap.L().Debug("returning namespaceDeleteReport with error(s)")
@@ -271,3 +816,20 @@ func (t *NamespaceProcessor) namespaceType(namespace *prisma_types.Namespace) (t
 
 	return types.CloudEntityTypeInvalid, fmt.Errorf("namespace %s has an unexpected and invalid annotation", namespace.Name)
 }
+
+// joinPath joins parent and name with a slash
+func joinPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "/" + name
+}
+
+// splitPath returns the parent path and the name of path
+func splitPath(path string) (string, string) {
+	i := strings.LastIndex(path, "/")
+	if i < 0 {
+		return "", path
+	}
+	return path[:i], path[i+1:]
+}
@@ -0,0 +1,374 @@
+package processors
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// deleteConfig returns a config deleting compute namespaces without a blast radius limit
+func deleteConfig() *types.CloudOperatorConfig {
+	config := &types.CloudOperatorConfig{}
+	config.AddOps(types.OpNamespaceComputeDelete)
+	config.SetNamespaceDeleteMaxPercent(100)
+	return config
+}
+
+func tombstoneAnnotations(marked time.Time, runs string) map[string][]string {
+	return map[string][]string{
+		namespaceTombstoneKey:     {marked.UTC().Format(time.RFC3339)},
+		namespaceTombstoneRunsKey: {runs},
+	}
+}
+
+// findReport returns the report of the namespace at path
+func findReport(report *types.NamespaceReports, path string) *types.NamespaceReport {
+	for _, v := range report.Namespaces {
+		if v.Name == path {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestNamespaceRetire(t *testing.T) {
+
+	now := time.Now()
+
+	tests := map[string]struct {
+		annotations map[string][]string
+		operation   types.NamespaceOperation
+		status      types.OperationStatus
+		runs        int
+		updated     []string
+		deleted     []string
+	}{
+		"first run unused": {
+			operation: types.NamespaceOperationTombstone,
+			status:    types.OperationStatusCompleted,
+			runs:      1,
+			updated:   []string{"old"},
+		},
+		"grace not yet elapsed": {
+			annotations: tombstoneAnnotations(now.Add(-time.Hour), "4"),
+			operation:   types.NamespaceOperationTombstone,
+			status:      types.OperationStatusPending,
+			runs:        5,
+			updated:     []string{"old"},
+		},
+		"grace elapsed": {
+			annotations: tombstoneAnnotations(now.Add(-25*time.Hour), "2"),
+			operation:   types.NamespaceOperationDelete,
+			status:      types.OperationStatusCompleted,
+			runs:        3,
+			deleted:     []string{"old"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			namespaces := newFakeNamespaces()
+			namespace := namespaces.add("", "old", types.CloudEntityTypeCompute, test.annotations)
+
+			processor, err := namespaces.processor(deleteConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			report := findReport(processor.Process(context.Background()), "old")
+			if report == nil {
+				t.Fatalf("no report for namespace old")
+			}
+
+			if report.Operation != test.operation || report.Status != test.status || report.TombstoneRuns != test.runs {
+				t.Fatalf("got %s %s runs %d, want %s %s runs %d", report.Operation, report.Status, report.TombstoneRuns, test.operation, test.status, test.runs)
+			}
+
+			if !reflect.DeepEqual(namespaces.updated, test.updated) || !reflect.DeepEqual(namespaces.deleted, test.deleted) {
+				t.Fatalf("got updated %v deleted %v, want updated %v deleted %v", namespaces.updated, namespaces.deleted, test.updated, test.deleted)
+			}
+
+			if test.operation == types.NamespaceOperationTombstone {
+				mark, err := getTombstone(namespace)
+				if err != nil || mark == nil || mark.runs != test.runs {
+					t.Fatalf("tombstone not written, got %v %v", mark, err)
+				}
+			}
+		})
+	}
+}
+
+func TestNamespaceRestore(t *testing.T) {
+
+	namespaces := newFakeNamespaces()
+	namespace := namespaces.add("", "role", types.CloudEntityTypeCompute, tombstoneAnnotations(time.Now().Add(-time.Hour), "3"))
+
+	processor, err := namespaces.processor(deleteConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processor.AddCompute(types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role"))
+
+	report := findReport(processor.Process(context.Background()), "role")
+	if report == nil || report.Operation != types.NamespaceOperationRestore || report.Status != types.OperationStatusCompleted {
+		t.Fatalf("expected a completed restore, got %+v", report)
+	}
+
+	if _, ok := namespace.Annotations[namespaceTombstoneKey]; ok {
+		t.Fatalf("%s not cleared", namespaceTombstoneKey)
+	}
+
+	if _, ok := namespace.Annotations[namespaceTombstoneRunsKey]; ok {
+		t.Fatalf("%s not cleared", namespaceTombstoneRunsKey)
+	}
+
+	if !reflect.DeepEqual(namespaces.updated, []string{"role"}) || len(namespaces.deleted) > 0 {
+		t.Fatalf("got updated %v deleted %v, want updated [role]", namespaces.updated, namespaces.deleted)
+	}
+}
+
+func TestNamespaceChildrenExcludedByPattern(t *testing.T) {
+
+	namespaces := newFakeNamespaces()
+	namespaces.add("", "old", types.CloudEntityTypeCompute, tombstoneAnnotations(time.Now().Add(-48*time.Hour), "5"))
+	namespaces.add("old", "manual", types.CloudEntityTypeDefault, nil)
+
+	config := deleteConfig()
+	config.NamespaceDeletePatterns.AddExclude("manual")
+
+	processor, err := namespaces.processor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := findReport(processor.Process(context.Background()), "old")
+	if report == nil || report.Operation != types.NamespaceOperationIgnore || report.Status != types.OperationStatusSkipped {
+		t.Fatalf("expected old to be skipped, got %+v", report)
+	}
+
+	if len(namespaces.updated) > 0 || len(namespaces.deleted) > 0 {
+		t.Fatalf("got updated %v deleted %v, want none", namespaces.updated, namespaces.deleted)
+	}
+}
+
+func TestNamespaceLayoutChildren(t *testing.T) {
+
+	namespaces := newFakeNamespaces()
+	namespaces.add("", "compute", types.CloudEntityTypeLayout, nil)
+	namespaces.add("compute", "role", types.CloudEntityTypeCompute, nil)
+	namespaces.add("compute", "old", types.CloudEntityTypeCompute, tombstoneAnnotations(time.Now().Add(-48*time.Hour), "5"))
+
+	config := deleteConfig()
+	config.SetNamespaceLayout(types.NamespaceLayoutTyped)
+
+	processor, err := namespaces.processor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processor.AddCompute(types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role"))
+
+	report := processor.Process(context.Background())
+
+	// The child is walked and retired on its own; the layout namespace is in use
+	if !reflect.DeepEqual(namespaces.deleted, []string{"compute/old"}) {
+		t.Fatalf("got deleted %v, want [compute/old]", namespaces.deleted)
+	}
+
+	if findReport(report, "compute") != nil {
+		t.Fatalf("layout namespace compute must not be retired")
+	}
+
+	// A layout namespace is not retired in the same run as its last child
+	namespaces = newFakeNamespaces()
+	namespaces.add("", "compute", types.CloudEntityTypeLayout, nil)
+	namespaces.add("compute", "old", types.CloudEntityTypeCompute, nil)
+
+	processor, err = namespaces.processor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processor.Process(context.Background())
+
+	if !reflect.DeepEqual(sorted(namespaces.updated), []string{"compute/old"}) {
+		t.Fatalf("got updated %v, want only the child tombstoned", namespaces.updated)
+	}
+}
+
+func TestNamespaceCheckBlastRadius(t *testing.T) {
+
+	tests := map[string]struct {
+		maxCount   int
+		maxPercent int
+		override   bool
+		count      int
+		total      int
+		wantErr    bool
+	}{
+		"nothing to delete": {
+			count: 0,
+			total: 0,
+		},
+		"nothing to delete of none": {
+			maxCount: 1,
+			count:    0,
+			total:    0,
+		},
+		"count equals maximum count": {
+			maxCount:   3,
+			maxPercent: 100,
+			count:      3,
+			total:      10,
+		},
+		"count exceeds maximum count": {
+			maxCount:   3,
+			maxPercent: 100,
+			count:      4,
+			total:      10,
+			wantErr:    true,
+		},
+		"percent equals default maximum percent": {
+			count: 5,
+			total: 10,
+		},
+		"percent exceeds default maximum percent": {
+			count:   6,
+			total:   10,
+			wantErr: true,
+		},
+		"percent equals maximum percent": {
+			maxPercent: 20,
+			count:      2,
+			total:      10,
+		},
+		"percent exceeds maximum percent": {
+			maxPercent: 20,
+			count:      3,
+			total:      10,
+			wantErr:    true,
+		},
+		"zero total": {
+			count:   1,
+			total:   0,
+			wantErr: true,
+		},
+		"override exceeding maximum count": {
+			maxCount: 1,
+			override: true,
+			count:    10,
+			total:    10,
+		},
+		"override exceeding maximum percent": {
+			override: true,
+			count:    10,
+			total:    10,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			config := (&types.CloudOperatorConfig{}).
+				SetNamespaceDeleteMaxCount(test.maxCount).
+				SetNamespaceDeleteMaxPercent(test.maxPercent)
+
+			processor, err := newFakeNamespaces().processor(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			processor.SetDeleteOverride(test.override)
+
+			err = processor.checkBlastRadius(test.count, test.total)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNamespaceDeleteOverrideIsPerRun(t *testing.T) {
+
+	namespaces := newFakeNamespaces()
+	namespaces.add("", "old1", types.CloudEntityTypeCompute, tombstoneAnnotations(time.Now().Add(-48*time.Hour), "5"))
+	namespaces.add("", "old2", types.CloudEntityTypeCompute, tombstoneAnnotations(time.Now().Add(-48*time.Hour), "5"))
+
+	config := (&types.CloudOperatorConfig{}).SetNamespaceDeleteMaxCount(1)
+	config.AddOps(types.OpNamespaceComputeDelete)
+
+	// Without the override the run is aborted
+	processor, err := namespaces.processor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := processor.Process(context.Background())
+	if report.Error == nil || len(namespaces.deleted) > 0 {
+		t.Fatalf("expected the run to be aborted, got error %v deleted %v", report.Error, namespaces.deleted)
+	}
+
+	// The override set for a run applies to that run only
+	processor, err = namespaces.processor(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processor.SetDeleteOverride(true)
+	processor.Process(context.Background())
+
+	if !reflect.DeepEqual(sorted(namespaces.deleted), []string{"old1", "old2"}) {
+		t.Fatalf("got deleted %v, want [old1 old2]", namespaces.deleted)
+	}
+}
+
+func TestNamespaceLegacyNames(t *testing.T) {
+
+	tests := map[string]struct {
+		ops       []types.Op
+		operation types.NamespaceOperation
+		updated   []string
+	}{
+		"kept without the migration op": {
+			ops:       []types.Op{types.OpNamespaceComputeDelete},
+			operation: types.NamespaceOperationIgnore,
+		},
+		"retired with the migration op": {
+			ops:       []types.Op{types.OpNamespaceMigrateNames},
+			operation: types.NamespaceOperationTombstone,
+			updated:   []string{"sa@p1.iam"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			namespaces := newFakeNamespaces()
+			namespaces.add("", "sa@p1.iam", types.CloudEntityTypeCompute, nil)
+
+			config := (&types.CloudOperatorConfig{}).SetNamespaceDeleteMaxPercent(100)
+			config.AddOps(test.ops...)
+
+			processor, err := namespaces.processor(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			processor.AddCompute(types.NewNamespaceEntity(types.CloudEntityTypeCompute, "sa@p1.iam"))
+
+			report := findReport(processor.Process(context.Background()), "sa@p1.iam")
+			if report == nil || report.Operation != test.operation {
+				t.Fatalf("expected %s, got %+v", test.operation, report)
+			}
+
+			if !reflect.DeepEqual(namespaces.updated, test.updated) || len(namespaces.deleted) > 0 {
+				t.Fatalf("got updated %v deleted %v, want updated %v", namespaces.updated, namespaces.deleted, test.updated)
+			}
+		})
+	}
+}
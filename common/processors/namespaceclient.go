@@ -0,0 +1,56 @@
+package processors
+
+import (
+	"context"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// namespaceClient is the part of the Prisma client used by the NamespaceProcessor to manage
+// the namespaces below a single namespace
+type namespaceClient interface {
+	namespaces() []*prisma_types.Namespace
+	has(name string) bool
+	create(ctx context.Context, namespace *prisma_types.Namespace) error
+	update(ctx context.Context, namespace *prisma_types.Namespace) error
+	remove(ctx context.Context, name string) error
+	child(ctx context.Context, name string) (namespaceClient, error)
+}
+
+// prismaNamespaceClient is the namespaceClient of a Prisma API client
+type prismaNamespaceClient struct {
+	client *prisma_api.Client
+}
+
+func (t *prismaNamespaceClient) namespaces() []*prisma_types.Namespace {
+	return t.client.GetNamespaces()
+}
+
+func (t *prismaNamespaceClient) has(name string) bool {
+	return t.client.HasNamespace(name)
+}
+
+func (t *prismaNamespaceClient) create(ctx context.Context, namespace *prisma_types.Namespace) error {
+	_, err := t.client.CreateNamespace(ctx, namespace)
+	return err
+}
+
+func (t *prismaNamespaceClient) update(ctx context.Context, namespace *prisma_types.Namespace) error {
+	_, err := t.client.UpdateNamespace(ctx, namespace)
+	return err
+}
+
+func (t *prismaNamespaceClient) remove(ctx context.Context, name string) error {
+	return t.client.DeleteNamespace(ctx, name)
+}
+
+func (t *prismaNamespaceClient) child(ctx context.Context, name string) (namespaceClient, error) {
+
+	client, err := t.client.NewClient(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &prismaNamespaceClient{client: client}, nil
+}
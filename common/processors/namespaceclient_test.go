@@ -0,0 +1,114 @@
+package processors
+
+import (
+	"context"
+	"sort"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+
+	"github.com/aporeto-se/cloud-operator/common/layout"
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// fakeNamespaces is an in memory namespace tree shared by the fake clients. Namespaces are
+// keyed by the path of their parent relative to the cloud account namespace.
+type fakeNamespaces struct {
+	children map[string][]*prisma_types.Namespace
+	created  []string
+	updated  []string
+	deleted  []string
+}
+
+func newFakeNamespaces() *fakeNamespaces {
+	return &fakeNamespaces{children: make(map[string][]*prisma_types.Namespace)}
+}
+
+// add adds the namespace name of type ptype below parent with the extra annotations and returns it
+func (t *fakeNamespaces) add(parent, name string, ptype types.CloudEntityType, annotations map[string][]string) *prisma_types.Namespace {
+
+	namespace := &prisma_types.Namespace{
+		Name:        name,
+		Annotations: map[string][]string{namespaceAnnotationKey: {string(ptype)}},
+	}
+
+	for key, values := range annotations {
+		namespace.Annotations[key] = values
+	}
+
+	t.children[parent] = append(t.children[parent], namespace)
+	return namespace
+}
+
+// client returns the client of the cloud account namespace
+func (t *fakeNamespaces) client() *fakeNamespaceClient {
+	return &fakeNamespaceClient{tree: t}
+}
+
+// processor returns a NamespaceProcessor for config backed by the fake namespaces
+func (t *fakeNamespaces) processor(config *types.CloudOperatorConfig) (*NamespaceProcessor, error) {
+
+	namespaceLayout, err := layout.NewNamespaceLayout(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NamespaceProcessor{
+		cloudOperatorConfig: config,
+		layout:              namespaceLayout,
+		clients:             map[string]namespaceClient{"": t.client()},
+		created:             make(map[string]bool),
+	}, nil
+}
+
+func sorted(v []string) []string {
+	result := append([]string{}, v...)
+	sort.Strings(result)
+	return result
+}
+
+type fakeNamespaceClient struct {
+	tree *fakeNamespaces
+	path string
+}
+
+func (t *fakeNamespaceClient) namespaces() []*prisma_types.Namespace {
+	return t.tree.children[t.path]
+}
+
+func (t *fakeNamespaceClient) has(name string) bool {
+	for _, namespace := range t.tree.children[t.path] {
+		if namespace.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *fakeNamespaceClient) create(ctx context.Context, namespace *prisma_types.Namespace) error {
+	t.tree.children[t.path] = append(t.tree.children[t.path], namespace)
+	t.tree.created = append(t.tree.created, joinPath(t.path, namespace.Name))
+	return nil
+}
+
+func (t *fakeNamespaceClient) update(ctx context.Context, namespace *prisma_types.Namespace) error {
+	t.tree.updated = append(t.tree.updated, joinPath(t.path, namespace.Name))
+	return nil
+}
+
+func (t *fakeNamespaceClient) remove(ctx context.Context, name string) error {
+
+	var remaining []*prisma_types.Namespace
+	for _, namespace := range t.tree.children[t.path] {
+		if namespace.Name != name {
+			remaining = append(remaining, namespace)
+		}
+	}
+
+	t.tree.children[t.path] = remaining
+	t.tree.deleted = append(t.tree.deleted, joinPath(t.path, name))
+	return nil
+}
+
+func (t *fakeNamespaceClient) child(ctx context.Context, name string) (namespaceClient, error) {
+	return &fakeNamespaceClient{tree: t.tree, path: joinPath(t.path, name)}, nil
+}
@@ -0,0 +1,189 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"go.uber.org/zap"
+	k8sauthorizationv1 "k8s.io/api/authorization/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/layout"
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// accessRequirement is a Kubernetes permission required by an op
+type accessRequirement struct {
+	op        types.Op
+	verb      string
+	group     string
+	resource  string
+	namespace string
+	name      string
+}
+
+// Preflight verifies with a SelfSubjectAccessReview each Kubernetes permission required by
+// the enabled ops. Nothing is changed on the cluster.
+func (t *KubeProcessor) Preflight(ctx context.Context) []*types.PreflightCheck {
+
+	zap.L().Debug("entering Preflight")
+
+	var result []*types.PreflightCheck
+
+	requirements := t.accessRequirements()
+
+	if len(requirements) > 0 && t.KubernetesClientset == nil {
+		zap.L().Debug("returning Preflight; kubernetesClientset is required")
+		return append(result, types.NewPreflightCheck("", t.name, "kubernetes client").
+			SetError(fmt.Errorf("kubernetesClientset is required")))
+	}
+
+	for _, requirement := range requirements {
+
+		check := types.NewPreflightCheck(requirement.op, t.name, requirement.String())
+
+		review, err := t.KubernetesClientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &k8sauthorizationv1.SelfSubjectAccessReview{
+			Spec: k8sauthorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &k8sauthorizationv1.ResourceAttributes{
+					Verb:      requirement.verb,
+					Group:     requirement.group,
+					Resource:  requirement.resource,
+					Namespace: requirement.namespace,
+					Name:      requirement.name,
+				},
+			},
+		}, k8smetav1.CreateOptions{})
+
+		switch {
+
+		case err != nil:
+			check.SetError(err)
+
+		case !review.Status.Allowed:
+			reason := review.Status.Reason
+			if reason == "" {
+				reason = "denied"
+			}
+			check.SetError(fmt.Errorf("%s", reason))
+
+		default:
+			check.SetError(nil)
+
+		}
+
+		result = append(result, check)
+	}
+
+	zap.L().Debug("returning Preflight")
+	return result
+}
+
+// NamespacePreflight verifies the Prisma namespace at path relative to the namespace of
+// parent can be read. A namespace that does not exist yet is skipped.
+func NamespacePreflight(ctx context.Context, parent *prisma_api.Client, path, target string) *types.PreflightCheck {
+
+	check := types.NewPreflightCheck("", target, "read namespace "+path)
+
+	exists, err := layout.Exists(ctx, parent, path)
+	if err != nil {
+		return check.SetError(err)
+	}
+
+	if !exists {
+		return check.SetSkip("namespace does not exist yet")
+	}
+
+	_, err = layout.NewClient(ctx, parent, path)
+	return check.SetError(err)
+}
+
+// accessRequirements returns the Kubernetes permissions required by the enabled ops. The
+// calls are taken from the permissions table and scoped to the enforcer objects.
+func (t *KubeProcessor) accessRequirements() []*accessRequirement {
+
+	var objects []*managedObject
+	var ops []types.Op
+
+	installEnforcer := t.skipEnforcer == ""
+	if export, _ := t.cloudOperatorConfig.MatchKubeExport(t.name, t.tags); export {
+		installEnforcer = false
+	}
+
+	if t.KubernetesDaemonsetBuilder != nil {
+		objects = t.managedObjects(t.KubernetesDaemonsetBuilder.Build())
+	}
+
+	for _, op := range t.cloudOperatorConfig.Ops {
+
+		switch op {
+
+		case types.OpKubeEnforcer:
+			if installEnforcer && len(objects) > 0 {
+				ops = append(ops, op)
+			}
+
+		case types.OpKubeEnforcerUninstall:
+			if len(objects) > 0 {
+				ops = append(ops, op)
+			}
+
+		default:
+			ops = append(ops, op)
+
+		}
+	}
+
+	var result []*accessRequirement
+
+	for _, call := range permissions.Calls(permissions.ProviderKubernetes, ops) {
+
+		namespace := call.Namespace
+		name := call.Name
+
+		for _, object := range objects {
+			if object.group == call.Group && object.resource == call.Resource {
+				namespace = object.namespace
+				name = object.name
+			}
+		}
+
+		if call.EnforcerNamespace {
+			namespace = objects[0].namespace
+		}
+
+		for _, op := range ops {
+
+			if !call.HasOp(op) {
+				continue
+			}
+
+			for _, verb := range call.Verbs {
+				result = append(result, &accessRequirement{op: op, verb: verb, group: call.Group, resource: call.Resource, namespace: namespace, name: name})
+			}
+		}
+	}
+
+	return result
+}
+
+// String returns the requirement as "verb group/resource namespace/name"
+func (t *accessRequirement) String() string {
+
+	resource := t.resource
+	if t.group != "" {
+		resource = t.group + "/" + resource
+	}
+
+	name := t.name
+	if t.namespace != "" {
+		name = t.namespace + "/" + name
+	}
+
+	if name == "" {
+		return t.verb + " " + resource
+	}
+
+	return t.verb + " " + resource + " " + name
+}
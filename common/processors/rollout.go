@@ -0,0 +1,137 @@
+package processors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	k8sappsv1 "k8s.io/api/apps/v1"
+	k8scorev1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// rolloutPollInterval is the time between DaemonSet status checks
+const rolloutPollInterval = 5 * time.Second
+
+// degradedReasons are container waiting reasons that will not resolve without intervention
+var degradedReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"ImagePullBackOff":           true,
+	"ErrImagePull":               true,
+	"InvalidImageName":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"RunContainerError":          true,
+}
+
+// Rollout returns the rollout report of the enforcer DaemonSet or nil if the enforcer was not installed
+func (t *KubeProcessor) Rollout() *types.RolloutReport {
+	return t.rollout
+}
+
+// waitForRollout polls the DaemonSet until every scheduled pod runs the applied generation
+// and is ready, a pod is failing or the rollout timeout expires
+func (t *KubeProcessor) waitForRollout(ctx context.Context, daemonset *k8sappsv1.DaemonSet) (*types.RolloutReport, error) {
+
+	zap.L().Debug("entering waitForRollout")
+
+	ctx, cancel := context.WithTimeout(ctx, t.cloudOperatorConfig.GetKubeRolloutTimeout())
+	defer cancel()
+
+	ticker := time.NewTicker(rolloutPollInterval)
+	defer ticker.Stop()
+
+	generation := daemonset.Generation
+	client := t.KubernetesClientset.AppsV1().DaemonSets(daemonset.Namespace)
+
+	for {
+
+		current, err := client.Get(ctx, daemonset.Name, k8smetav1.GetOptions{})
+		if err != nil {
+			if ctx.Err() != nil {
+				// The wait ended while the request was in flight
+				zap.L().Debug("returning waitForRollout (timed out)")
+				return types.NewRolloutReport().SetStatus(types.RolloutStatusProgressing), nil
+			}
+			zap.L().Debug("returning waitForRollout with error(s)")
+			return nil, err
+		}
+
+		status := current.Status
+		report := types.NewRolloutReport().
+			SetCounts(status.DesiredNumberScheduled, status.NumberReady, status.UpdatedNumberScheduled)
+
+		if status.ObservedGeneration >= generation &&
+			status.UpdatedNumberScheduled == status.DesiredNumberScheduled &&
+			status.NumberReady == status.DesiredNumberScheduled {
+			zap.L().Debug(fmt.Sprintf("returning waitForRollout; DaemonSet ready on %d nodes of cluster %s", status.NumberReady, t.name))
+			return report.SetStatus(types.RolloutStatusReady), nil
+		}
+
+		reasons, degraded := t.podReasons(ctx, current)
+		report.SetReasons(reasons)
+
+		if degraded {
+			zap.L().Debug(fmt.Sprintf("returning waitForRollout; DaemonSet degraded on cluster %s: %v", t.name, reasons))
+			return report.SetStatus(types.RolloutStatusDegraded), nil
+		}
+
+		select {
+
+		case <-ctx.Done():
+			zap.L().Debug(fmt.Sprintf("returning waitForRollout; DaemonSet still progressing on cluster %s (%d/%d ready)", t.name, status.NumberReady, status.DesiredNumberScheduled))
+			return report.SetStatus(types.RolloutStatusProgressing), nil
+
+		case <-ticker.C:
+
+		}
+	}
+}
+
+// podReasons returns the distinct reasons the DaemonSet pods are not ready and true if any
+// of them is a failure
+func (t *KubeProcessor) podReasons(ctx context.Context, daemonset *k8sappsv1.DaemonSet) ([]string, bool) {
+
+	if daemonset.Spec.Selector == nil {
+		return nil, false
+	}
+
+	pods, err := t.KubernetesClientset.CoreV1().Pods(daemonset.Namespace).List(ctx, k8smetav1.ListOptions{
+		LabelSelector: k8smetav1.FormatLabelSelector(daemonset.Spec.Selector),
+	})
+	if err != nil {
+		zap.L().Debug(fmt.Sprintf("unable to list DaemonSet pods on cluster %s: %s", t.name, err))
+		return nil, false
+	}
+
+	unique := make(map[string]bool)
+	degraded := false
+
+	for _, pod := range pods.Items {
+
+		statuses := append(append([]k8scorev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+		for _, containerStatus := range statuses {
+			if containerStatus.State.Waiting == nil || containerStatus.State.Waiting.Reason == "" {
+				continue
+			}
+			reason := containerStatus.State.Waiting.Reason
+			unique[reason] = true
+			if degradedReasons[reason] {
+				degraded = true
+			}
+		}
+	}
+
+	var reasons []string
+	for reason := range unique {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	return reasons, degraded
+}
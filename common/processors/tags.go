@@ -0,0 +1,66 @@
+package processors
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// tagAnnotations returns the annotations mirrored from the tags of entity that match the
+// allowlist. Keys are prefixed and values sorted so the result is stable between runs.
+func tagAnnotations(config *types.CloudOperatorConfig, entity *types.NamespaceEntity) (map[string][]string, error) {
+
+	result := make(map[string][]string)
+
+	for key, values := range entity.Tags {
+
+		match, err := config.MatchNamespaceTagKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if !match {
+			continue
+		}
+
+		sorted := append([]string{}, values...)
+		sort.Strings(sorted)
+		result[config.GetNamespaceTagPrefix()+key] = sorted
+	}
+
+	return result, nil
+}
+
+// syncTagAnnotations updates the annotations of namespace owned by the operator (those with
+// prefix) to match desired and returns true if anything changed
+func syncTagAnnotations(namespace *prisma_types.Namespace, prefix string, desired map[string][]string) bool {
+
+	changed := false
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string][]string)
+	}
+
+	for key := range namespace.Annotations {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if _, ok := desired[key]; !ok {
+			delete(namespace.Annotations, key)
+			changed = true
+		}
+	}
+
+	for key, values := range desired {
+		if !reflect.DeepEqual(namespace.Annotations[key], values) {
+			namespace.Annotations[key] = values
+			changed = true
+		}
+	}
+
+	return changed
+}
@@ -0,0 +1,121 @@
+package processors
+
+import (
+	"reflect"
+	"testing"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestTagAnnotations(t *testing.T) {
+
+	config := (&types.CloudOperatorConfig{}).AddNamespaceTagKeys("team", "cost-*")
+
+	entity := types.NewNamespaceEntity(types.CloudEntityTypeCompute, "role1").
+		AddTag("team", "web").
+		AddTag("team", "api").
+		AddTag("cost-center", "42").
+		AddTag("owner", "alice")
+
+	got, err := tagAnnotations(config, entity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][]string{
+		"Cloud-Tag-team":        {"api", "web"},
+		"Cloud-Tag-cost-center": {"42"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	config.AddNamespaceTagKeys("regex:(")
+
+	if _, err := tagAnnotations(config, entity); err == nil {
+		t.Fatalf("expected error for an invalid pattern")
+	}
+}
+
+func TestSyncTagAnnotations(t *testing.T) {
+
+	const prefix = types.DefaultNamespaceTagPrefix
+
+	tests := map[string]struct {
+		annotations map[string][]string
+		desired     map[string][]string
+		want        map[string][]string
+		changed     bool
+	}{
+		"nil annotations none desired": {
+			desired: map[string][]string{},
+			want:    map[string][]string{},
+		},
+		"added": {
+			annotations: map[string][]string{},
+			desired:     map[string][]string{prefix + "team": {"web"}},
+			want:        map[string][]string{prefix + "team": {"web"}},
+			changed:     true,
+		},
+		"added to nil annotations": {
+			desired: map[string][]string{prefix + "team": {"web"}},
+			want:    map[string][]string{prefix + "team": {"web"}},
+			changed: true,
+		},
+		"changed": {
+			annotations: map[string][]string{prefix + "team": {"web"}},
+			desired:     map[string][]string{prefix + "team": {"api", "web"}},
+			want:        map[string][]string{prefix + "team": {"api", "web"}},
+			changed:     true,
+		},
+		"removed": {
+			annotations: map[string][]string{prefix + "team": {"web"}, prefix + "env": {"prod"}},
+			desired:     map[string][]string{prefix + "team": {"web"}},
+			want:        map[string][]string{prefix + "team": {"web"}},
+			changed:     true,
+		},
+		"unchanged": {
+			annotations: map[string][]string{prefix + "team": {"web"}},
+			desired:     map[string][]string{prefix + "team": {"web"}},
+			want:        map[string][]string{prefix + "team": {"web"}},
+		},
+		"annotations without prefix left alone": {
+			annotations: map[string][]string{
+				namespaceAnnotationKey: {"COMPUTE"},
+				"team":                 {"manual"},
+				prefix + "team":        {"web"},
+			},
+			desired: map[string][]string{},
+			want: map[string][]string{
+				namespaceAnnotationKey: {"COMPUTE"},
+				"team":                 {"manual"},
+			},
+			changed: true,
+		},
+		"annotations without prefix unchanged": {
+			annotations: map[string][]string{namespaceAnnotationKey: {"COMPUTE"}, "team": {"manual"}},
+			desired:     map[string][]string{},
+			want:        map[string][]string{namespaceAnnotationKey: {"COMPUTE"}, "team": {"manual"}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			namespace := &prisma_types.Namespace{Name: "ns", Annotations: test.annotations}
+
+			changed := syncTagAnnotations(namespace, prefix, test.desired)
+
+			if changed != test.changed {
+				t.Fatalf("got changed %t, want %t", changed, test.changed)
+			}
+
+			if !reflect.DeepEqual(namespace.Annotations, test.want) {
+				t.Fatalf("got %v, want %v", namespace.Annotations, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package processors
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+)
+
+// tombstone is the unused mark placed on a namespace before it is deleted
+type tombstone struct {
+	time time.Time
+	runs int
+}
+
+// hasTombstone returns true if the namespace carries any tombstone annotation
+func hasTombstone(namespace *prisma_types.Namespace) bool {
+
+	if namespace.Annotations == nil {
+		return false
+	}
+
+	_, hasTime := namespace.Annotations[namespaceTombstoneKey]
+	_, hasRuns := namespace.Annotations[namespaceTombstoneRunsKey]
+
+	return hasTime || hasRuns
+}
+
+// getTombstone returns the tombstone of the namespace, nil if the namespace is not
+// tombstoned or an error if the tombstone annotations are invalid
+func getTombstone(namespace *prisma_types.Namespace) (*tombstone, error) {
+
+	if namespace.Annotations == nil {
+		return nil, nil
+	}
+
+	timeEntry := namespace.Annotations[namespaceTombstoneKey]
+	if len(timeEntry) == 0 {
+		return nil, nil
+	}
+
+	tombstoneTime, err := time.Parse(time.RFC3339, timeEntry[0])
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s has an invalid %s annotation: %w", namespace.Name, namespaceTombstoneKey, err)
+	}
+
+	runs := 1
+	runsEntry := namespace.Annotations[namespaceTombstoneRunsKey]
+	if len(runsEntry) > 0 {
+		runs, err = strconv.Atoi(runsEntry[0])
+		if err != nil {
+			return nil, fmt.Errorf("namespace %s has an invalid %s annotation: %w", namespace.Name, namespaceTombstoneRunsKey, err)
+		}
+	}
+
+	return &tombstone{
+		time: tombstoneTime,
+		runs: runs,
+	}, nil
+}
+
+// setTombstone sets the tombstone annotations on the namespace
+func setTombstone(namespace *prisma_types.Namespace, v *tombstone) {
+
+	if namespace.Annotations == nil {
+		namespace.Annotations = make(map[string][]string)
+	}
+
+	namespace.Annotations[namespaceTombstoneKey] = []string{v.time.UTC().Format(time.RFC3339)}
+	namespace.Annotations[namespaceTombstoneRunsKey] = []string{strconv.Itoa(v.runs)}
+}
+
+// clearTombstone removes the tombstone annotations from the namespace
+func clearTombstone(namespace *prisma_types.Namespace) {
+
+	if namespace.Annotations == nil {
+		return
+	}
+
+	delete(namespace.Annotations, namespaceTombstoneKey)
+	delete(namespace.Annotations, namespaceTombstoneRunsKey)
+}
+
+// expired returns true if the tombstone has reached the grace period or the grace runs.
+// A zero gracePeriod or graceRuns is not considered.
+func (t *tombstone) expired(now time.Time, gracePeriod time.Duration, graceRuns int) bool {
+
+	if gracePeriod > 0 && now.Sub(t.time) >= gracePeriod {
+		return true
+	}
+
+	if graceRuns > 0 && t.runs >= graceRuns {
+		return true
+	}
+
+	return false
+}
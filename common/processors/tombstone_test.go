@@ -0,0 +1,111 @@
+package processors
+
+import (
+	"testing"
+	"time"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+func TestTombstoneExpired(t *testing.T) {
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		config *types.CloudOperatorConfig
+		age    time.Duration
+		runs   int
+		want   bool
+	}{
+		"default grace not yet elapsed": {
+			config: &types.CloudOperatorConfig{},
+			age:    23 * time.Hour,
+			runs:   10,
+			want:   false,
+		},
+		"default grace elapsed": {
+			config: &types.CloudOperatorConfig{},
+			age:    24 * time.Hour,
+			runs:   2,
+			want:   true,
+		},
+		"grace runs only with too few runs": {
+			config: (&types.CloudOperatorConfig{}).SetNamespaceDeleteGraceRuns(3),
+			age:    30 * 24 * time.Hour,
+			runs:   2,
+			want:   false,
+		},
+		"grace runs only reached": {
+			config: (&types.CloudOperatorConfig{}).SetNamespaceDeleteGraceRuns(3),
+			age:    time.Minute,
+			runs:   3,
+			want:   true,
+		},
+		"grace period elapsed with too few runs": {
+			config: (&types.CloudOperatorConfig{}).SetNamespaceDeleteGracePeriod(3600).SetNamespaceDeleteGraceRuns(3),
+			age:    time.Hour,
+			runs:   2,
+			want:   true,
+		},
+		"neither grace period nor runs reached": {
+			config: (&types.CloudOperatorConfig{}).SetNamespaceDeleteGracePeriod(3600).SetNamespaceDeleteGraceRuns(3),
+			age:    59 * time.Minute,
+			runs:   2,
+			want:   false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			mark := &tombstone{time: now.Add(-test.age), runs: test.runs}
+
+			got := mark.expired(now, test.config.GetNamespaceDeleteGracePeriod(), test.config.GetNamespaceDeleteGraceRuns())
+			if got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestTombstoneAnnotations(t *testing.T) {
+
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	namespace := &prisma_types.Namespace{Name: "ns"}
+
+	mark, err := getTombstone(namespace)
+	if err != nil || mark != nil || hasTombstone(namespace) {
+		t.Fatalf("expected no tombstone, got %v %v", mark, err)
+	}
+
+	setTombstone(namespace, &tombstone{time: now, runs: 2})
+
+	mark, err = getTombstone(namespace)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !mark.time.Equal(now) || mark.runs != 2 {
+		t.Fatalf("got %v %d, want %v 2", mark.time, mark.runs, now)
+	}
+
+	clearTombstone(namespace)
+
+	if _, ok := namespace.Annotations[namespaceTombstoneKey]; ok {
+		t.Fatalf("%s not cleared", namespaceTombstoneKey)
+	}
+
+	if _, ok := namespace.Annotations[namespaceTombstoneRunsKey]; ok {
+		t.Fatalf("%s not cleared", namespaceTombstoneRunsKey)
+	}
+
+	namespace.Annotations[namespaceTombstoneKey] = []string{"yesterday"}
+
+	_, err = getTombstone(namespace)
+	if err == nil {
+		t.Fatalf("expected error for an invalid tombstone time")
+	}
+}
@@ -1,7 +1,12 @@
 package reportwrapper
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/aporeto-se/cloud-operator/common/types"
 )
@@ -9,6 +14,10 @@ import (
 // Wrapper ...
 type Wrapper struct {
 	reports *types.KubernetesReports
+	slots   chan struct{}
+	hung    chan struct{}
+	timeout time.Duration
+	wg      sync.WaitGroup
 	sync.Mutex
 }
 
@@ -19,6 +28,26 @@ func NewWrapper() *Wrapper {
 	}
 }
 
+// SetConcurrency sets the maximum number of runs executed at the same time and returns
+// self. A value of zero or less means unbounded. Must be called before RunKube.
+func (t *Wrapper) SetConcurrency(v int) *Wrapper {
+	if v > 0 {
+		t.slots = make(chan struct{}, v)
+		t.hung = make(chan struct{}, v)
+	} else {
+		t.slots = nil
+		t.hung = nil
+	}
+	return t
+}
+
+// SetTimeout sets the deadline for each run and returns self. A value of zero or less
+// means no deadline. Must be called before RunKube.
+func (t *Wrapper) SetTimeout(v time.Duration) *Wrapper {
+	t.timeout = v
+	return t
+}
+
 // AddKube ...
 func (t *Wrapper) AddKube(report *types.KubernetesReport) {
 	t.Lock()
@@ -26,8 +55,129 @@ func (t *Wrapper) AddKube(report *types.KubernetesReport) {
 	t.reports.AddReports(report)
 }
 
-// Build ...
+// RunKube executes fn in a new goroutine as soon as a slot is available and adds the
+// returned report. If a timeout is set fn is given a context with that deadline. If fn
+// has not returned when the deadline expires a TIMED_OUT report for name is added so a
+// hung cluster does not delay the report. A timed out fn that ignores ctx hands its slot
+// to the next cluster; at most concurrency such runs are abandoned at the same time; after
+// that a timed out run keeps its slot until fn returns.
+func (t *Wrapper) RunKube(ctx context.Context, name string, fn func(ctx context.Context) *types.KubernetesReport) {
+
+	t.wg.Add(1)
+
+	go func() {
+
+		defer t.wg.Done()
+
+		slot := &slot{release: func() {}}
+
+		if t.slots != nil {
+			select {
+			case t.slots <- struct{}{}:
+				slot.release = func() { <-t.slots }
+			case <-ctx.Done():
+				t.AddKube(types.NewKubernetesReport(name).
+					SetStatus(types.OpStatusTimedOut).
+					SetError(fmt.Errorf("cluster %s was not started: %w", name, ctx.Err())))
+				return
+			}
+		}
+
+		// The slot and the cancellation may be ready at the same time
+		if ctx.Err() != nil {
+			slot.done()
+			t.AddKube(types.NewKubernetesReport(name).
+				SetStatus(types.OpStatusTimedOut).
+				SetError(fmt.Errorf("cluster %s was not started: %w", name, ctx.Err())))
+			return
+		}
+
+		if t.timeout <= 0 {
+			defer slot.done()
+			t.AddKube(fn(ctx))
+			return
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, t.timeout)
+
+		result := make(chan *types.KubernetesReport, 1)
+
+		go func() {
+			defer slot.done()
+			defer cancel()
+			result <- fn(runCtx)
+		}()
+
+		select {
+
+		case report := <-result:
+			t.addResult(runCtx, report)
+
+		case <-runCtx.Done():
+
+			// runCtx is also cancelled once fn has returned
+			select {
+			case report := <-result:
+				t.addResult(runCtx, report)
+				return
+			default:
+			}
+
+			slot.handOver(t.hung)
+
+			zap.L().Warn(fmt.Sprintf("cluster %s did not complete within %s", name, t.timeout))
+			t.AddKube(types.NewKubernetesReport(name).
+				SetStatus(types.OpStatusTimedOut).
+				SetError(fmt.Errorf("cluster %s did not complete within %s", name, t.timeout)))
+
+		}
+	}()
+}
+
+// slot is the concurrency slot held by a run
+type slot struct {
+	release  func()
+	returned bool
+	sync.Mutex
+}
+
+// done releases the slot once fn has returned
+func (t *slot) done() {
+	t.Lock()
+	defer t.Unlock()
+	t.returned = true
+	t.release()
+}
+
+// handOver releases the slot of a timed out run that has not returned if a token is
+// available in hung. The run then gives back its hung token instead once fn returns.
+func (t *slot) handOver(hung chan struct{}) {
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.returned || hung == nil {
+		return
+	}
+
+	select {
+	case hung <- struct{}{}:
+		t.release()
+		t.release = func() { <-hung }
+	default:
+	}
+}
+
+func (t *Wrapper) addResult(runCtx context.Context, report *types.KubernetesReport) {
+	if report.Error != nil && runCtx.Err() == context.DeadlineExceeded {
+		report.SetStatus(types.OpStatusTimedOut)
+	}
+	t.AddKube(report)
+}
+
+// Build waits for all runs to complete and returns the reports
 func (t *Wrapper) Build() *types.KubernetesReports {
+	t.wg.Wait()
 	t.Lock()
 	defer t.Unlock()
 	return t.reports
@@ -0,0 +1,192 @@
+package reportwrapper
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// counter tracks the number of fns running at the same time
+type counter struct {
+	running int
+	max     int
+	sync.Mutex
+}
+
+func (t *counter) enter() {
+	t.Lock()
+	defer t.Unlock()
+	t.running++
+	if t.running > t.max {
+		t.max = t.running
+	}
+}
+
+func (t *counter) maximum() int {
+	t.Lock()
+	defer t.Unlock()
+	return t.max
+}
+
+func (t *counter) leave() {
+	t.Lock()
+	defer t.Unlock()
+	t.running--
+}
+
+func reportsByName(reports *types.KubernetesReports) map[string]*types.KubernetesReport {
+	result := make(map[string]*types.KubernetesReport)
+	for _, report := range reports.Reports {
+		result[report.Name] = report
+	}
+	return result
+}
+
+func TestRunKubeTimeout(t *testing.T) {
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	wrapper := NewWrapper().SetConcurrency(1).SetTimeout(20 * time.Millisecond)
+
+	// fn ignores ctx
+	wrapper.RunKube(context.Background(), "hung", func(ctx context.Context) *types.KubernetesReport {
+		<-hang
+		return types.NewKubernetesReport("hung")
+	})
+
+	report := reportsByName(wrapper.Build())["hung"]
+	if report == nil || report.Status != types.OpStatusTimedOut || report.Error == nil {
+		t.Fatalf("expected a TIMED_OUT report, got %+v", report)
+	}
+}
+
+func TestRunKubeHungClustersDoNotStall(t *testing.T) {
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	wrapper := NewWrapper().SetConcurrency(1).SetTimeout(20 * time.Millisecond)
+
+	wrapper.RunKube(ctx, "hung", func(ctx context.Context) *types.KubernetesReport {
+		<-hang
+		return types.NewKubernetesReport("hung")
+	})
+
+	// Give the hung cluster the slot first
+	time.Sleep(5 * time.Millisecond)
+
+	wrapper.RunKube(ctx, "ok", func(ctx context.Context) *types.KubernetesReport {
+		return types.NewKubernetesReport("ok").SetStatus(types.OpStatusCompleted)
+	})
+
+	reports := reportsByName(wrapper.Build())
+
+	if ctx.Err() != nil {
+		t.Fatalf("run stalled behind the hung cluster")
+	}
+
+	if reports["ok"] == nil || reports["ok"].Status != types.OpStatusCompleted {
+		t.Fatalf("expected ok to complete, got %+v", reports["ok"])
+	}
+}
+
+func TestRunKubeAbandonedRunsAreBounded(t *testing.T) {
+
+	hang := make(chan struct{})
+	defer close(hang)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	wrapper := NewWrapper().SetConcurrency(1).SetTimeout(20 * time.Millisecond)
+	counter := &counter{}
+
+	for _, name := range []string{"hung1", "hung2", "hung3", "hung4"} {
+		wrapper.RunKube(ctx, name, func(ctx context.Context) *types.KubernetesReport {
+			counter.enter()
+			<-hang
+			counter.leave()
+			return nil
+		})
+	}
+
+	wrapper.Build()
+
+	// One slot plus one abandoned run
+	if max := counter.maximum(); max > 2 {
+		t.Fatalf("%d runs at the same time, want at most 2", max)
+	}
+}
+
+func TestRunKubeQueueing(t *testing.T) {
+
+	wrapper := NewWrapper().SetConcurrency(2).SetTimeout(time.Second)
+	counter := &counter{}
+
+	names := []string{"c1", "c2", "c3", "c4", "c5", "c6"}
+
+	for _, name := range names {
+		name := name
+		wrapper.RunKube(context.Background(), name, func(ctx context.Context) *types.KubernetesReport {
+			counter.enter()
+			defer counter.leave()
+			time.Sleep(10 * time.Millisecond)
+			return types.NewKubernetesReport(name).SetStatus(types.OpStatusCompleted)
+		})
+	}
+
+	reports := reportsByName(wrapper.Build())
+
+	if max := counter.maximum(); max > 2 {
+		t.Fatalf("%d runs at the same time, want at most 2", max)
+	}
+
+	for _, name := range names {
+		if reports[name] == nil || reports[name].Status != types.OpStatusCompleted {
+			t.Fatalf("expected %s to complete, got %+v", name, reports[name])
+		}
+	}
+}
+
+func TestRunKubeParentCancel(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	wrapper := NewWrapper().SetConcurrency(1)
+
+	started := make(chan struct{})
+
+	wrapper.RunKube(ctx, "running", func(ctx context.Context) *types.KubernetesReport {
+		close(started)
+		<-ctx.Done()
+		return types.NewKubernetesReport("running").SetError(ctx.Err())
+	})
+
+	<-started
+
+	wrapper.RunKube(ctx, "queued", func(ctx context.Context) *types.KubernetesReport {
+		t.Errorf("queued cluster must not run after the parent is cancelled")
+		return types.NewKubernetesReport("queued")
+	})
+
+	cancel()
+
+	reports := reportsByName(wrapper.Build())
+
+	if reports["running"] == nil || reports["running"].Error == nil {
+		t.Fatalf("expected running to return the cancel error, got %+v", reports["running"])
+	}
+
+	queued := reports["queued"]
+	if queued == nil || queued.Status != types.OpStatusTimedOut || !strings.Contains(queued.Error.Error(), "was not started") {
+		t.Fatalf("expected queued to be reported as not started, got %+v", queued)
+	}
+}
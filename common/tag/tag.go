@@ -60,7 +60,9 @@ func (t *Matcher) filter2MatchKubeCluster(clusterName string, clusterTags map[st
 		return true
 	}
 
-	if t.filter2.KubeMatchAny {
+	// A request filter without kube criteria (for example a compute only filter) does not
+	// narrow the clusters
+	if t.filter2.KubeMatchAny || (len(t.filter2.KubeMatchNames) == 0 && len(t.filter2.KubeMatchTags) == 0) {
 		return true
 	}
 
@@ -97,3 +99,48 @@ func (t *Matcher) MatchKubeCluster(clusterName string, clusterTags map[string]st
 
 	return false
 }
+
+// InScopeKubeCluster returns true if the cluster matches the config filter (filter1). The
+// request filter only narrows a run so a cluster is only out of scope, and uninstalled, if
+// the config filter does not match it.
+func (t *Matcher) InScopeKubeCluster(clusterName string, clusterTags map[string]string) bool {
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.filter1MatchKubeCluster(clusterName, clusterTags)
+}
+
+// MatchComputeInstance returns true if instance matches both filters. An empty or nil
+// filter matches every instance.
+func (t *Matcher) MatchComputeInstance(instance *types.ComputeInstance) bool {
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.filter1 != nil && !t.filter1.MatchComputeInstance(instance) {
+		return false
+	}
+
+	if t.filter2 != nil && !t.filter2.MatchComputeInstance(instance) {
+		return false
+	}
+
+	return true
+}
+
+// InScopeComputeInstance returns true if instance matches the config filter (filter1) in any
+// state. The request filter only narrows a run and a stopped instance still uses its account,
+// so the namespace of an account is only out of scope, and deleted, if the config filter does
+// not match any of its instances.
+func (t *Matcher) InScopeComputeInstance(instance *types.ComputeInstance) bool {
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.filter1 == nil {
+		return true
+	}
+
+	return t.filter1.MatchComputeInstanceAnyState(instance)
+}
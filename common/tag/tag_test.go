@@ -1,5 +1,11 @@
 package tag
 
+import (
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
 // import (
 // 	"testing"
 // )
@@ -72,3 +78,110 @@ package tag
 // 	}
 
 // }
+
+func TestMatchKubeCluster(t *testing.T) {
+
+	filter1 := types.NewFilter().AddKubeMatchNames("cluster1", "cluster2").AddKubeMatchTag("env", "prod")
+
+	tests := map[string]struct {
+		filter2 *types.Filter
+		name    string
+		tags    map[string]string
+		want    bool
+	}{
+		"no request filter":             {filter2: nil, name: "cluster1", want: true},
+		"no request filter no match":    {filter2: nil, name: "cluster3", want: false},
+		"request filter without kube":   {filter2: types.NewFilter().AddComputeMatchStates("running"), name: "cluster1", want: true},
+		"request filter without kube 2": {filter2: types.NewFilter(), name: "cluster3", tags: map[string]string{"env": "prod"}, want: true},
+		"request filter without kube 3": {filter2: types.NewFilter(), name: "cluster3", want: false},
+		"request filter any":            {filter2: types.NewExampleFilterMatchAny(), name: "cluster2", want: true},
+		"request filter name":           {filter2: types.NewFilter().AddKubeMatchNames("cluster2"), name: "cluster2", want: true},
+		"request filter narrows":        {filter2: types.NewFilter().AddKubeMatchNames("cluster2"), name: "cluster1", want: false},
+		"request filter tag":            {filter2: types.NewFilter().AddKubeMatchTag("team", "a"), name: "cluster1", tags: map[string]string{"team": "a"}, want: true},
+		"request filter tag mismatch":   {filter2: types.NewFilter().AddKubeMatchTag("team", "a"), name: "cluster1", tags: map[string]string{"team": "b"}, want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			matcher, err := NewMatcher(filter1, test.filter2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := matcher.MatchKubeCluster(test.name, test.tags); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMatchComputeInstance(t *testing.T) {
+
+	instance := types.NewComputeInstance("instance").
+		AddTag("env", "prod").
+		AddNetworks("network1").
+		SetState("running")
+
+	tests := map[string]struct {
+		filter1 *types.Filter
+		filter2 *types.Filter
+		want    bool
+	}{
+		"empty filters":          {filter1: types.NewFilter(), filter2: nil, want: true},
+		"config filter match":    {filter1: types.NewFilter().AddComputeMatchNetworks("network1"), filter2: nil, want: true},
+		"config filter no match": {filter1: types.NewFilter().AddComputeMatchNetworks("network2"), filter2: nil, want: false},
+		"request filter match":   {filter1: types.NewFilter(), filter2: types.NewFilter().AddComputeMatchTag("env", "prod"), want: true},
+		"request filter narrows": {filter1: types.NewFilter().AddComputeMatchNetworks("network1"), filter2: types.NewFilter().AddComputeMatchStates("stopped"), want: false},
+		"both match":             {filter1: types.NewFilter().AddComputeMatchNetworks("network1"), filter2: types.NewFilter().AddComputeMatchStates("RUNNING"), want: true},
+		"kube only filters":      {filter1: types.NewExampleFilterMatchAny(), filter2: types.NewExampleFilterMatchNames(), want: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			matcher, err := NewMatcher(test.filter1, test.filter2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := matcher.MatchComputeInstance(instance); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestInScopeComputeInstance(t *testing.T) {
+
+	instance := types.NewComputeInstance("instance").
+		AddTag("env", "prod").
+		AddNetworks("network1").
+		SetState("stopped")
+
+	tests := map[string]struct {
+		filter1 *types.Filter
+		filter2 *types.Filter
+		want    bool
+	}{
+		"empty filters":                {filter1: types.NewFilter(), want: true},
+		"config filter match":          {filter1: types.NewFilter().AddComputeMatchNetworks("network1"), want: true},
+		"config filter no match":       {filter1: types.NewFilter().AddComputeMatchNetworks("network2"), want: false},
+		"request filter is not scope":  {filter1: types.NewFilter(), filter2: types.NewFilter().AddComputeMatchTag("env", "dev"), want: true},
+		"config filter states ignored": {filter1: types.NewFilter().AddComputeMatchStates("running"), want: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			matcher, err := NewMatcher(test.filter1, test.filter2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := matcher.InScopeComputeInstance(instance); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
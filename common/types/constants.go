@@ -4,6 +4,10 @@ const (
 	// PrismaPrependEnv is appended to the env var of each Prisma env var
 	PrismaPrependEnv = "PRISMA_"
 
+	// ConfigFileEnv enviroment variable. If set the YAML or JSON file is read before
+	// any other enviroment variable.
+	ConfigFileEnv = PrismaPrependEnv + "CONFIG_FILE"
+
 	// ConfigVersionEnv enviroment variable
 	ConfigVersionEnv = PrismaPrependEnv + "CONFIG_VERSION"
 
@@ -33,4 +37,132 @@ const (
 
 	// KubeMatchAnyEnv enviroment variable
 	KubeMatchAnyEnv = PrismaPrependEnv + "KUBE_MATCH_ANY"
+
+	// ComputeMatchTagsEnv enviroment variable
+	ComputeMatchTagsEnv = PrismaPrependEnv + "COMPUTE_MATCH_TAGS"
+
+	// ComputeMatchNetworksEnv enviroment variable
+	ComputeMatchNetworksEnv = PrismaPrependEnv + "COMPUTE_MATCH_NETWORKS"
+
+	// ComputeMatchSubnetsEnv enviroment variable
+	ComputeMatchSubnetsEnv = PrismaPrependEnv + "COMPUTE_MATCH_SUBNETS"
+
+	// ComputeMatchAccountsEnv enviroment variable
+	ComputeMatchAccountsEnv = PrismaPrependEnv + "COMPUTE_MATCH_ACCOUNTS"
+
+	// ComputeMatchStatesEnv enviroment variable
+	ComputeMatchStatesEnv = PrismaPrependEnv + "COMPUTE_MATCH_STATES"
+
+	// KubeConcurrencyEnv enviroment variable
+	KubeConcurrencyEnv = PrismaPrependEnv + "KUBE_CONCURRENCY"
+
+	// KubeClusterTimeoutEnv enviroment variable
+	KubeClusterTimeoutEnv = PrismaPrependEnv + "KUBE_CLUSTER_TIMEOUT"
+
+	// KubeDriftModeEnv enviroment variable
+	KubeDriftModeEnv = PrismaPrependEnv + "KUBE_DRIFT_MODE"
+
+	// KubeExportDirEnv enviroment variable
+	KubeExportDirEnv = PrismaPrependEnv + "KUBE_EXPORT_DIR"
+
+	// KubeExportFormatEnv enviroment variable
+	KubeExportFormatEnv = PrismaPrependEnv + "KUBE_EXPORT_FORMAT"
+
+	// KubeExportMatchNamesEnv enviroment variable
+	KubeExportMatchNamesEnv = PrismaPrependEnv + "KUBE_EXPORT_MATCH_NAMES"
+
+	// KubeExportMatchTagsEnv enviroment variable
+	KubeExportMatchTagsEnv = PrismaPrependEnv + "KUBE_EXPORT_MATCH_TAGS"
+
+	// KubeRolloutTimeoutEnv enviroment variable
+	KubeRolloutTimeoutEnv = PrismaPrependEnv + "KUBE_ROLLOUT_TIMEOUT"
+
+	// NamespaceDeleteGracePeriodEnv enviroment variable
+	NamespaceDeleteGracePeriodEnv = PrismaPrependEnv + "NS_DELETE_GRACE_PERIOD"
+
+	// NamespaceDeleteGraceRunsEnv enviroment variable
+	NamespaceDeleteGraceRunsEnv = PrismaPrependEnv + "NS_DELETE_GRACE_RUNS"
+
+	// NamespaceDeleteMaxCountEnv enviroment variable
+	NamespaceDeleteMaxCountEnv = PrismaPrependEnv + "NS_DELETE_MAX_COUNT"
+
+	// NamespaceDeleteMaxPercentEnv enviroment variable
+	NamespaceDeleteMaxPercentEnv = PrismaPrependEnv + "NS_DELETE_MAX_PERCENT"
+
+	// NamespaceCreateIncludeEnv enviroment variable
+	NamespaceCreateIncludeEnv = PrismaPrependEnv + "NS_CREATE_INCLUDE"
+
+	// NamespaceCreateExcludeEnv enviroment variable
+	NamespaceCreateExcludeEnv = PrismaPrependEnv + "NS_CREATE_EXCLUDE"
+
+	// NamespaceDeleteIncludeEnv enviroment variable
+	NamespaceDeleteIncludeEnv = PrismaPrependEnv + "NS_DELETE_INCLUDE"
+
+	// NamespaceDeleteExcludeEnv enviroment variable
+	NamespaceDeleteExcludeEnv = PrismaPrependEnv + "NS_DELETE_EXCLUDE"
+
+	// NamespaceLayoutEnv enviroment variable
+	NamespaceLayoutEnv = PrismaPrependEnv + "NS_LAYOUT"
+
+	// NamespaceComputeTemplateEnv enviroment variable
+	NamespaceComputeTemplateEnv = PrismaPrependEnv + "NS_COMPUTE_TEMPLATE"
+
+	// NamespaceKubeTemplateEnv enviroment variable
+	NamespaceKubeTemplateEnv = PrismaPrependEnv + "NS_KUBE_TEMPLATE"
+
+	// EnforcerImageRegistryEnv enviroment variable
+	EnforcerImageRegistryEnv = PrismaPrependEnv + "ENFORCER_IMAGE_REGISTRY"
+
+	// EnforcerImagePullSecretsEnv enviroment variable
+	EnforcerImagePullSecretsEnv = PrismaPrependEnv + "ENFORCER_IMAGE_PULL_SECRETS"
+
+	// EnforcerPriorityClassNameEnv enviroment variable
+	EnforcerPriorityClassNameEnv = PrismaPrependEnv + "ENFORCER_PRIORITY_CLASS_NAME"
+
+	// DHCPModeEnv enviroment variable
+	DHCPModeEnv = PrismaPrependEnv + "DHCP_MODE"
+
+	// NamespaceTagKeysEnv enviroment variable
+	NamespaceTagKeysEnv = PrismaPrependEnv + "NS_TAG_KEYS"
+
+	// NamespaceTagPrefixEnv enviroment variable
+	NamespaceTagPrefixEnv = PrismaPrependEnv + "NS_TAG_PREFIX"
+
+	// KubeWorkloadAuthRoleEnv enviroment variable
+	KubeWorkloadAuthRoleEnv = PrismaPrependEnv + "KUBE_WORKLOAD_AUTH_ROLE"
+
+	// KubeWorkloadAuthIncludeEnv enviroment variable
+	KubeWorkloadAuthIncludeEnv = PrismaPrependEnv + "KUBE_WORKLOAD_AUTH_INCLUDE"
+
+	// KubeWorkloadAuthExcludeEnv enviroment variable
+	KubeWorkloadAuthExcludeEnv = PrismaPrependEnv + "KUBE_WORKLOAD_AUTH_EXCLUDE"
+)
+
+const (
+	// regexPatternPrefix marks a namespace pattern as a regular expression
+	regexPatternPrefix = "regex:"
+)
+
+const (
+	// DefaultKubeConcurrency is the default number of Kubernetes clusters processed at the same time
+	DefaultKubeConcurrency = 5
+
+	// DefaultKubeClusterTimeout is the default time in seconds allowed to process a single Kubernetes cluster
+	DefaultKubeClusterTimeout = 300
+
+	// DefaultKubeRolloutTimeout is the default time in seconds to wait for the enforcer DaemonSet to become ready
+	DefaultKubeRolloutTimeout = 120
+
+	// DefaultNamespaceDeleteGracePeriod is the default time in seconds an unused namespace is kept before deletion
+	DefaultNamespaceDeleteGracePeriod = 86400
+
+	// DefaultNamespaceDeleteMaxPercent is the default maximum percentage of namespaces deleted in a single run
+	DefaultNamespaceDeleteMaxPercent = 50
+
+	// DefaultNamespaceTagPrefix is the default prefix of the annotations mirrored from cloud tags
+	DefaultNamespaceTagPrefix = "Cloud-Tag-"
+
+	// DefaultKubeWorkloadAuthRole is the default role granted to workload identities. It is
+	// read only so a workload can never act as an enforcer unless configured to.
+	DefaultKubeWorkloadAuthRole = "@auth:role=namespace.viewer"
 )
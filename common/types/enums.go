@@ -67,6 +67,9 @@ const (
 
 	// CloudEntityTypeDefault default
 	CloudEntityTypeDefault CloudEntityType = "DEFAULT"
+
+	// CloudEntityTypeLayout intermediate namespace created by the namespace layout
+	CloudEntityTypeLayout CloudEntityType = "LAYOUT"
 )
 
 // CloudEntityTypeFromString returns type from string or error
@@ -83,6 +86,9 @@ func CloudEntityTypeFromString(s string) (CloudEntityType, error) {
 	case string(CloudEntityTypeDefault):
 		return CloudEntityTypeDefault, nil
 
+	case string(CloudEntityTypeLayout):
+		return CloudEntityTypeLayout, nil
+
 	}
 
 	return CloudEntityTypeInvalid, fmt.Errorf("string %s is not a valid type", s)
@@ -100,6 +106,9 @@ const (
 	// OpDHCP DHCP
 	OpDHCP Op = "DHCP"
 
+	// OpInfraServices Metadata, DNS and NTP cloud infrastructure services
+	OpInfraServices Op = "INFRA_SERVICES"
+
 	// OpNamespaceRogueDelete Namespace Rogue Delete
 	OpNamespaceRogueDelete Op = "NS_ROGUE_DELETE"
 
@@ -115,12 +124,18 @@ const (
 	// OpNamespaceKubeDelete Namespace Kube Delete
 	OpNamespaceKubeDelete Op = "NS_KUBE_DELETE"
 
+	// OpNamespaceMigrateNames Namespace Delete of namespaces named before names were sanitized
+	OpNamespaceMigrateNames Op = "NS_MIGRATE_NAMES"
+
 	// OpComputeAuth Compute Auth
 	OpComputeAuth Op = "COMPUTE_AUTH"
 
 	// OpKubeAuth Kube Auth
 	OpKubeAuth Op = "KUBE_AUTH"
 
+	// OpKubeWorkloadAuth Kube Workload Identity (IRSA and GKE Workload Identity) Auth
+	OpKubeWorkloadAuth Op = "KUBE_WORKLOAD_AUTH"
+
 	// OpKubeAPINet API Network List
 	OpKubeAPINet Op = "KUBE_API_NET"
 
@@ -130,8 +145,14 @@ const (
 	// OpKubeNodesNet Nodes (Workers) Network List
 	OpKubeNodesNet Op = "KUBE_NODES_NET"
 
+	// OpKubeClusterNets Pod, Service and Node Subnet Network Lists
+	OpKubeClusterNets Op = "KUBE_CLUSTER_NETS"
+
 	// OpKubeEnforcer Enforcer (Daemonset) Install
 	OpKubeEnforcer Op = "KUBE_ENFORCER"
+
+	// OpKubeEnforcerUninstall Enforcer (Daemonset) Uninstall from clusters out of scope
+	OpKubeEnforcerUninstall Op = "KUBE_ENFORCER_UNINSTALL"
 )
 
 // OpFromString returns type from string or error
@@ -142,6 +163,9 @@ func OpFromString(s string) (Op, error) {
 	case string(OpDHCP):
 		return OpDHCP, nil
 
+	case string(OpInfraServices):
+		return OpInfraServices, nil
+
 	case string(OpNamespaceRogueDelete):
 		return OpNamespaceRogueDelete, nil
 
@@ -157,12 +181,18 @@ func OpFromString(s string) (Op, error) {
 	case string(OpNamespaceKubeDelete):
 		return OpNamespaceKubeDelete, nil
 
+	case string(OpNamespaceMigrateNames):
+		return OpNamespaceMigrateNames, nil
+
 	case string(OpComputeAuth):
 		return OpComputeAuth, nil
 
 	case string(OpKubeAuth):
 		return OpKubeAuth, nil
 
+	case string(OpKubeWorkloadAuth):
+		return OpKubeWorkloadAuth, nil
+
 	case string(OpKubeAPINet):
 		return OpKubeAPINet, nil
 
@@ -172,9 +202,15 @@ func OpFromString(s string) (Op, error) {
 	case string(OpKubeNodesNet):
 		return OpKubeNodesNet, nil
 
+	case string(OpKubeClusterNets):
+		return OpKubeClusterNets, nil
+
 	case string(OpKubeEnforcer):
 		return OpKubeEnforcer, nil
 
+	case string(OpKubeEnforcerUninstall):
+		return OpKubeEnforcerUninstall, nil
+
 	}
 
 	return OpInvalid, fmt.Errorf("string %s is not a valid type", s)
@@ -200,6 +236,9 @@ const (
 
 	// OpStatusNotReady failed
 	OpStatusNotReady OpStatus = "NOT_READY"
+
+	// OpStatusTimedOut timed out
+	OpStatusTimedOut OpStatus = "TIMED_OUT"
 )
 
 // OpStatusFromString returns type from string or error
@@ -219,6 +258,9 @@ func OpStatusFromString(s string) (OpStatus, error) {
 	case string(OpStatusNotReady):
 		return OpStatusNotReady, nil
 
+	case string(OpStatusTimedOut):
+		return OpStatusTimedOut, nil
+
 	}
 
 	return OpStatusInvalid, fmt.Errorf("string %s is not a valid type", s)
@@ -241,6 +283,15 @@ const (
 
 	// NamespaceOperationIgnore ignore
 	NamespaceOperationIgnore NamespaceOperation = "IGNORE"
+
+	// NamespaceOperationTombstone mark as unused pending deletion
+	NamespaceOperationTombstone NamespaceOperation = "TOMBSTONE"
+
+	// NamespaceOperationRestore remove unused mark
+	NamespaceOperationRestore NamespaceOperation = "RESTORE"
+
+	// NamespaceOperationUpdate update existing namespace
+	NamespaceOperationUpdate NamespaceOperation = "UPDATE"
 )
 
 // NamespaceOperationFromString returns type from string or error
@@ -257,6 +308,15 @@ func NamespaceOperationFromString(s string) (NamespaceOperation, error) {
 	case string(NamespaceOperationIgnore):
 		return NamespaceOperationIgnore, nil
 
+	case string(NamespaceOperationTombstone):
+		return NamespaceOperationTombstone, nil
+
+	case string(NamespaceOperationRestore):
+		return NamespaceOperationRestore, nil
+
+	case string(NamespaceOperationUpdate):
+		return NamespaceOperationUpdate, nil
+
 	}
 
 	return NamespaceOperationInvalid, fmt.Errorf("string %s is not a valid type", s)
@@ -279,6 +339,12 @@ const (
 
 	// OperationStatusFailed failed
 	OperationStatusFailed OperationStatus = "FAILED"
+
+	// OperationStatusPending pending
+	OperationStatusPending OperationStatus = "PENDING"
+
+	// OperationStatusSkipped skipped
+	OperationStatusSkipped OperationStatus = "SKIPPED"
 )
 
 // OperationStatusromString returns type from string or error
@@ -295,9 +361,307 @@ func OperationStatusromString(s string) (OperationStatus, error) {
 	case string(OperationStatusFailed):
 		return OperationStatusFailed, nil
 
+	case string(OperationStatusPending):
+		return OperationStatusPending, nil
+
+	case string(OperationStatusSkipped):
+		return OperationStatusSkipped, nil
+
 	}
 
 	return OperationStatusInvalid, fmt.Errorf("string %s is not a valid type", s)
 }
 
 // ================================================================================================
+
+// NamespaceLayoutType is the strategy used to place namespaces below the cloud account namespace
+type NamespaceLayoutType string
+
+const (
+	// NamespaceLayoutInvalid invalid
+	NamespaceLayoutInvalid NamespaceLayoutType = "INVALID"
+
+	// NamespaceLayoutFlat <name>
+	NamespaceLayoutFlat NamespaceLayoutType = "FLAT"
+
+	// NamespaceLayoutTyped compute/<name> and kube/<name>
+	NamespaceLayoutTyped NamespaceLayoutType = "TYPED"
+
+	// NamespaceLayoutNetwork <region>/<network>/<name> for clusters and <region>/<name> for compute
+	NamespaceLayoutNetwork NamespaceLayoutType = "NETWORK"
+)
+
+// NamespaceLayoutTypeFromString returns type from string or error
+func NamespaceLayoutTypeFromString(s string) (NamespaceLayoutType, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(NamespaceLayoutFlat):
+		return NamespaceLayoutFlat, nil
+
+	case string(NamespaceLayoutTyped):
+		return NamespaceLayoutTyped, nil
+
+	case string(NamespaceLayoutNetwork):
+		return NamespaceLayoutNetwork, nil
+
+	}
+
+	return NamespaceLayoutInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// KubernetesOperation KubernetesOperation
+type KubernetesOperation string
+
+const (
+	// KubernetesOperationInvalid invalid
+	KubernetesOperationInvalid KubernetesOperation = "INVALID"
+
+	// KubernetesOperationSync cluster is in scope and the enabled ops were applied
+	KubernetesOperationSync KubernetesOperation = "SYNC"
+
+	// KubernetesOperationUninstall cluster is out of scope and managed objects were removed
+	KubernetesOperationUninstall KubernetesOperation = "UNINSTALL"
+)
+
+// KubernetesOperationFromString returns type from string or error
+func KubernetesOperationFromString(s string) (KubernetesOperation, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(KubernetesOperationSync):
+		return KubernetesOperationSync, nil
+
+	case string(KubernetesOperationUninstall):
+		return KubernetesOperationUninstall, nil
+
+	}
+
+	return KubernetesOperationInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// RolloutStatus RolloutStatus
+type RolloutStatus string
+
+const (
+	// RolloutStatusInvalid invalid
+	RolloutStatusInvalid RolloutStatus = "INVALID"
+
+	// RolloutStatusReady every scheduled pod is updated and ready
+	RolloutStatusReady RolloutStatus = "READY"
+
+	// RolloutStatusProgressing pods are not yet ready when the wait ended
+	RolloutStatusProgressing RolloutStatus = "PROGRESSING"
+
+	// RolloutStatusDegraded pods are failing (crash loop, image pull, ...)
+	RolloutStatusDegraded RolloutStatus = "DEGRADED"
+)
+
+// RolloutStatusFromString returns type from string or error
+func RolloutStatusFromString(s string) (RolloutStatus, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(RolloutStatusReady):
+		return RolloutStatusReady, nil
+
+	case string(RolloutStatusProgressing):
+		return RolloutStatusProgressing, nil
+
+	case string(RolloutStatusDegraded):
+		return RolloutStatusDegraded, nil
+
+	}
+
+	return RolloutStatusInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// DriftMode DriftMode
+type DriftMode string
+
+const (
+	// DriftModeInvalid invalid
+	DriftModeInvalid DriftMode = "INVALID"
+
+	// DriftModeOff objects are applied without checking for drift
+	DriftModeOff DriftMode = "OFF"
+
+	// DriftModeDetect drift is reported and only missing objects are applied
+	DriftModeDetect DriftMode = "DETECT"
+
+	// DriftModeEnforce drift is reported and corrected by applying the objects
+	DriftModeEnforce DriftMode = "ENFORCE"
+)
+
+// DriftModeFromString returns type from string or error
+func DriftModeFromString(s string) (DriftMode, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(DriftModeOff):
+		return DriftModeOff, nil
+
+	case string(DriftModeDetect):
+		return DriftModeDetect, nil
+
+	case string(DriftModeEnforce):
+		return DriftModeEnforce, nil
+
+	}
+
+	return DriftModeInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// DriftStatus is the state of a live object compared with the desired object
+type DriftStatus string
+
+const (
+	// DriftStatusInvalid invalid
+	DriftStatusInvalid DriftStatus = "INVALID"
+
+	// DriftStatusMissing the object does not exist
+	DriftStatusMissing DriftStatus = "MISSING"
+
+	// DriftStatusDrifted the object differs from the desired object
+	DriftStatusDrifted DriftStatus = "DRIFTED"
+
+	// DriftStatusUnmanaged the object exists but is owned by another field manager
+	DriftStatusUnmanaged DriftStatus = "UNMANAGED"
+)
+
+// DriftStatusFromString returns type from string or error
+func DriftStatusFromString(s string) (DriftStatus, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(DriftStatusMissing):
+		return DriftStatusMissing, nil
+
+	case string(DriftStatusDrifted):
+		return DriftStatusDrifted, nil
+
+	case string(DriftStatusUnmanaged):
+		return DriftStatusUnmanaged, nil
+
+	}
+
+	return DriftStatusInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// ExportFormat ExportFormat
+type ExportFormat string
+
+const (
+	// ExportFormatInvalid invalid
+	ExportFormatInvalid ExportFormat = "INVALID"
+
+	// ExportFormatYAML all objects are written to a single multi document YAML file
+	ExportFormatYAML ExportFormat = "YAML"
+
+	// ExportFormatKustomize each object is written to its own file with a kustomization.yaml
+	ExportFormatKustomize ExportFormat = "KUSTOMIZE"
+)
+
+// ExportFormatFromString returns type from string or error
+func ExportFormatFromString(s string) (ExportFormat, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(ExportFormatYAML):
+		return ExportFormatYAML, nil
+
+	case string(ExportFormatKustomize):
+		return ExportFormatKustomize, nil
+
+	}
+
+	return ExportFormatInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// PreflightStatus PreflightStatus
+type PreflightStatus string
+
+const (
+	// PreflightStatusInvalid invalid
+	PreflightStatusInvalid PreflightStatus = "INVALID"
+
+	// PreflightStatusPass the permission is granted
+	PreflightStatusPass PreflightStatus = "PASS"
+
+	// PreflightStatusFail the permission is missing or could not be verified
+	PreflightStatusFail PreflightStatus = "FAIL"
+
+	// PreflightStatusSkip the check was not run
+	PreflightStatusSkip PreflightStatus = "SKIP"
+)
+
+// PreflightStatusFromString returns type from string or error
+func PreflightStatusFromString(s string) (PreflightStatus, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(PreflightStatusPass):
+		return PreflightStatusPass, nil
+
+	case string(PreflightStatusFail):
+		return PreflightStatusFail, nil
+
+	case string(PreflightStatusSkip):
+		return PreflightStatusSkip, nil
+
+	}
+
+	return PreflightStatusInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
+
+// DHCPMode DHCPMode
+type DHCPMode string
+
+const (
+	// DHCPModeInvalid invalid
+	DHCPModeInvalid DHCPMode = "INVALID"
+
+	// DHCPModeAccount a single set of DHCP policies for the cloud account
+	DHCPModeAccount DHCPMode = "ACCOUNT"
+
+	// DHCPModeNetwork a set of DHCP policies per network (AWS VPC or GCP VPC network)
+	DHCPModeNetwork DHCPMode = "NETWORK"
+
+	// DHCPModeSubnet a set of DHCP policies per subnet (AWS subnet or GCP subnetwork)
+	DHCPModeSubnet DHCPMode = "SUBNET"
+)
+
+// DHCPModeFromString returns type from string or error
+func DHCPModeFromString(s string) (DHCPMode, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(DHCPModeAccount):
+		return DHCPModeAccount, nil
+
+	case string(DHCPModeNetwork):
+		return DHCPModeNetwork, nil
+
+	case string(DHCPModeSubnet):
+		return DHCPModeSubnet, nil
+
+	}
+
+	return DHCPModeInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
@@ -3,10 +3,13 @@ package types
 import (
 	"fmt"
 	"os"
+	"path"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // ================================================================================================
@@ -35,6 +38,96 @@ type CloudOperatorConfig struct {
 
 	// Filter the filter
 	Filter Filter `json:"filter" yaml:"filter"`
+
+	// KubeConcurrency is the maximum number of Kubernetes clusters processed at the same
+	// time. If not set DefaultKubeConcurrency is used.
+	KubeConcurrency int `json:"kubeConcurrency,omitempty" yaml:"kubeConcurrency,omitempty"`
+
+	// KubeClusterTimeout is the maximum time in seconds allowed to process a single
+	// Kubernetes cluster. If not set DefaultKubeClusterTimeout is used.
+	KubeClusterTimeout int `json:"kubeClusterTimeout,omitempty" yaml:"kubeClusterTimeout,omitempty"`
+
+	// KubeRolloutTimeout is the maximum time in seconds to wait for the enforcer DaemonSet
+	// to become ready. It must be less than KubeClusterTimeout. If not set
+	// DefaultKubeRolloutTimeout is used.
+	KubeRolloutTimeout int `json:"kubeRolloutTimeout,omitempty" yaml:"kubeRolloutTimeout,omitempty"`
+
+	// KubeDriftMode controls how live enforcer objects that differ from the desired objects
+	// are handled. If not set DriftModeOff is used.
+	KubeDriftMode DriftMode `json:"kubeDriftMode,omitempty" yaml:"kubeDriftMode,omitempty"`
+
+	// KubeExportDir is the directory (typically a git working tree) the enforcer objects of
+	// the clusters matching KubeExportMatchNames or KubeExportMatchTags are written to instead
+	// of being applied. Each cluster is written to the sub directory of its namespace path, for
+	// example <dir>/tenant/account/kube/cluster1. If not set objects are always applied.
+	KubeExportDir string `json:"kubeExportDir,omitempty" yaml:"kubeExportDir,omitempty"`
+
+	// KubeExportFormat is the format of the exported objects. If not set ExportFormatYAML is used.
+	KubeExportFormat ExportFormat `json:"kubeExportFormat,omitempty" yaml:"kubeExportFormat,omitempty"`
+
+	// KubeExportMatchNames selects the clusters that are exported. Names are globs or "regex:"
+	// patterns. If neither KubeExportMatchNames nor KubeExportMatchTags is set all clusters
+	// are exported.
+	KubeExportMatchNames []string `json:"kubeExportMatchNames,omitempty" yaml:"kubeExportMatchNames,omitempty"`
+
+	// KubeExportMatchTags selects the clusters with all of the tags for export
+	KubeExportMatchTags map[string]string `json:"kubeExportMatchTags,omitempty" yaml:"kubeExportMatchTags,omitempty"`
+
+	// KubeWorkloadAuthRole is the role granted to the cloud identities of annotated
+	// ServiceAccounts. If not set DefaultKubeWorkloadAuthRole is used.
+	KubeWorkloadAuthRole string `json:"kubeWorkloadAuthRole,omitempty" yaml:"kubeWorkloadAuthRole,omitempty"`
+
+	// KubeWorkloadAuthPatterns selects the ServiceAccounts (namespace/name) authorized by
+	// KUBE_WORKLOAD_AUTH. No ServiceAccount is authorized unless at least one include
+	// pattern is set.
+	KubeWorkloadAuthPatterns NamespacePatterns `json:"kubeWorkloadAuthPatterns,omitempty" yaml:"kubeWorkloadAuthPatterns,omitempty"`
+
+	// NamespaceDeleteGracePeriod is the time in seconds a namespace must have been marked as
+	// unused (tombstoned) before it is deleted. If neither NamespaceDeleteGracePeriod nor
+	// NamespaceDeleteGraceRuns is set DefaultNamespaceDeleteGracePeriod is used.
+	NamespaceDeleteGracePeriod int `json:"namespaceDeleteGracePeriod,omitempty" yaml:"namespaceDeleteGracePeriod,omitempty"`
+
+	// NamespaceDeleteGraceRuns is the number of consecutive runs a namespace must have been
+	// found unused before it is deleted. The namespace is deleted as soon as either the grace
+	// period or the number of runs is reached.
+	NamespaceDeleteGraceRuns int `json:"namespaceDeleteGraceRuns,omitempty" yaml:"namespaceDeleteGraceRuns,omitempty"`
+
+	// NamespaceDeleteMaxCount is the maximum number of namespaces that may be deleted in a
+	// single run. If exceeded no namespace is deleted. Zero means no limit.
+	NamespaceDeleteMaxCount int `json:"namespaceDeleteMaxCount,omitempty" yaml:"namespaceDeleteMaxCount,omitempty"`
+
+	// NamespaceDeleteMaxPercent is the maximum percentage of namespaces that may be deleted in
+	// a single run. If exceeded no namespace is deleted. If not set DefaultNamespaceDeleteMaxPercent
+	// is used.
+	NamespaceDeleteMaxPercent int `json:"namespaceDeleteMaxPercent,omitempty" yaml:"namespaceDeleteMaxPercent,omitempty"`
+
+	// NamespaceCreatePatterns limits the namespaces the operator creates
+	NamespaceCreatePatterns NamespacePatterns `json:"namespaceCreatePatterns,omitempty" yaml:"namespaceCreatePatterns,omitempty"`
+
+	// NamespaceDeletePatterns limits the namespaces the operator deletes. Manually created
+	// namespaces can be protected by excluding them here.
+	NamespaceDeletePatterns NamespacePatterns `json:"namespaceDeletePatterns,omitempty" yaml:"namespaceDeletePatterns,omitempty"`
+
+	// NamespaceLayout is the strategy used to place compute and Kubernetes namespaces below
+	// the cloud account namespace. If not set NamespaceLayoutFlat is used.
+	NamespaceLayout NamespaceLayoutType `json:"namespaceLayout,omitempty" yaml:"namespaceLayout,omitempty"`
+
+	// NamespaceTemplates are Go templates used to name compute and Kubernetes namespaces
+	NamespaceTemplates NamespaceTemplates `json:"namespaceTemplates,omitempty" yaml:"namespaceTemplates,omitempty"`
+
+	// NamespaceTagKeys is the allowlist of cloud tag and label keys mirrored onto namespaces as
+	// annotations. Keys are globs or "regex:" patterns. If empty tags are not synced.
+	NamespaceTagKeys []string `json:"namespaceTagKeys,omitempty" yaml:"namespaceTagKeys,omitempty"`
+
+	// NamespaceTagPrefix is prepended to the key of each mirrored annotation. Annotations
+	// with the prefix are owned by the operator. If not set DefaultNamespaceTagPrefix is used.
+	NamespaceTagPrefix string `json:"namespaceTagPrefix,omitempty" yaml:"namespaceTagPrefix,omitempty"`
+
+	// Enforcer is overlaid onto the enforcer DaemonSet of every cluster
+	Enforcer EnforcerSpec `json:"enforcer,omitempty" yaml:"enforcer,omitempty"`
+
+	// EnforcerOverrides are overlaid onto Enforcer for the clusters they match, in order
+	EnforcerOverrides []*EnforcerOverride `json:"enforcerOverrides,omitempty" yaml:"enforcerOverrides,omitempty"`
 }
 
 // SetFromEnv sets attributes and types from env variables as defined in
@@ -94,6 +187,144 @@ func (t *CloudOperatorConfig) SetFromEnv() error {
 		}
 	}
 
+	kubeConcurrency, err := GetEnvInt(KubeConcurrencyEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if kubeConcurrency > 0 {
+		t.KubeConcurrency = kubeConcurrency
+	}
+
+	kubeClusterTimeout, err := GetEnvInt(KubeClusterTimeoutEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if kubeClusterTimeout > 0 {
+		t.KubeClusterTimeout = kubeClusterTimeout
+	}
+
+	kubeDriftModeString := os.Getenv(KubeDriftModeEnv)
+	if kubeDriftModeString != "" {
+		kubeDriftMode, err := DriftModeFromString(kubeDriftModeString)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			t.KubeDriftMode = kubeDriftMode
+		}
+	}
+
+	kubeExportDir := os.Getenv(KubeExportDirEnv)
+	if kubeExportDir != "" {
+		t.KubeExportDir = kubeExportDir
+	}
+
+	kubeExportFormatString := os.Getenv(KubeExportFormatEnv)
+	if kubeExportFormatString != "" {
+		kubeExportFormat, err := ExportFormatFromString(kubeExportFormatString)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			t.KubeExportFormat = kubeExportFormat
+		}
+	}
+
+	t.AddKubeExportMatchNames(GetEnvList(KubeExportMatchNamesEnv)...)
+
+	// Tag format is the same as KubeMatchTagsEnv. Example: key1:value1,key2:value2
+	for _, keyValuePair := range GetEnvList(KubeExportMatchTagsEnv) {
+		keyValuePairSplit := strings.Split(keyValuePair, ":")
+		if len(keyValuePairSplit) != 2 {
+			errors = multierror.Append(errors, fmt.Errorf("keyValuePair %s has invalid syntax. Expected format is key:value", keyValuePair))
+		} else {
+			t.AddKubeExportMatchTag(strings.TrimSpace(keyValuePairSplit[0]), strings.TrimSpace(keyValuePairSplit[1]))
+		}
+	}
+
+	kubeWorkloadAuthRole := os.Getenv(KubeWorkloadAuthRoleEnv)
+	if kubeWorkloadAuthRole != "" {
+		t.KubeWorkloadAuthRole = kubeWorkloadAuthRole
+	}
+
+	t.KubeWorkloadAuthPatterns.AddInclude(GetEnvList(KubeWorkloadAuthIncludeEnv)...)
+	t.KubeWorkloadAuthPatterns.AddExclude(GetEnvList(KubeWorkloadAuthExcludeEnv)...)
+
+	kubeRolloutTimeout, err := GetEnvInt(KubeRolloutTimeoutEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if kubeRolloutTimeout > 0 {
+		t.KubeRolloutTimeout = kubeRolloutTimeout
+	}
+
+	namespaceDeleteGracePeriod, err := GetEnvInt(NamespaceDeleteGracePeriodEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if namespaceDeleteGracePeriod > 0 {
+		t.NamespaceDeleteGracePeriod = namespaceDeleteGracePeriod
+	}
+
+	namespaceDeleteGraceRuns, err := GetEnvInt(NamespaceDeleteGraceRunsEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if namespaceDeleteGraceRuns > 0 {
+		t.NamespaceDeleteGraceRuns = namespaceDeleteGraceRuns
+	}
+
+	namespaceDeleteMaxCount, err := GetEnvInt(NamespaceDeleteMaxCountEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if namespaceDeleteMaxCount > 0 {
+		t.NamespaceDeleteMaxCount = namespaceDeleteMaxCount
+	}
+
+	namespaceDeleteMaxPercent, err := GetEnvInt(NamespaceDeleteMaxPercentEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	} else if namespaceDeleteMaxPercent > 0 {
+		t.NamespaceDeleteMaxPercent = namespaceDeleteMaxPercent
+	}
+
+	namespaceLayoutString := os.Getenv(NamespaceLayoutEnv)
+	if namespaceLayoutString != "" {
+		namespaceLayout, err := NamespaceLayoutTypeFromString(namespaceLayoutString)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			t.NamespaceLayout = namespaceLayout
+		}
+	}
+
+	namespaceComputeTemplate := os.Getenv(NamespaceComputeTemplateEnv)
+	if namespaceComputeTemplate != "" {
+		t.NamespaceTemplates.Compute = namespaceComputeTemplate
+	}
+
+	namespaceKubeTemplate := os.Getenv(NamespaceKubeTemplateEnv)
+	if namespaceKubeTemplate != "" {
+		t.NamespaceTemplates.Kube = namespaceKubeTemplate
+	}
+
+	enforcerImageRegistry := os.Getenv(EnforcerImageRegistryEnv)
+	if enforcerImageRegistry != "" {
+		t.Enforcer.ImageRegistry = enforcerImageRegistry
+	}
+
+	enforcerPriorityClassName := os.Getenv(EnforcerPriorityClassNameEnv)
+	if enforcerPriorityClassName != "" {
+		t.Enforcer.PriorityClassName = enforcerPriorityClassName
+	}
+
+	t.Enforcer.ImagePullSecrets = append(t.Enforcer.ImagePullSecrets, GetEnvList(EnforcerImagePullSecretsEnv)...)
+
+	t.NamespaceTagKeys = append(t.NamespaceTagKeys, GetEnvList(NamespaceTagKeysEnv)...)
+
+	namespaceTagPrefix := os.Getenv(NamespaceTagPrefixEnv)
+	if namespaceTagPrefix != "" {
+		t.NamespaceTagPrefix = namespaceTagPrefix
+	}
+
+	t.NamespaceCreatePatterns.AddInclude(GetEnvList(NamespaceCreateIncludeEnv)...)
+	t.NamespaceCreatePatterns.AddExclude(GetEnvList(NamespaceCreateExcludeEnv)...)
+	t.NamespaceDeletePatterns.AddInclude(GetEnvList(NamespaceDeleteIncludeEnv)...)
+	t.NamespaceDeletePatterns.AddExclude(GetEnvList(NamespaceDeleteExcludeEnv)...)
+
 	err = t.Filter.SetFromEnv()
 	if err != nil {
 		errors = multierror.Append(errors, err)
@@ -166,47 +397,623 @@ func (t *CloudOperatorConfig) GetOrgTenant() (string, error) {
 	if t.OrgTenant == "" {
 		err = fmt.Errorf("attribute OrgTenant (env var %s) is required", OrgTenantEnv)
 	}
-	return t.OrgTenant, err
-}
+	return t.OrgTenant, err
+}
+
+// SetOrgCloudAccount sets attribute and returns self
+func (t *CloudOperatorConfig) SetOrgCloudAccount(v string) *CloudOperatorConfig {
+	t.OrgCloudAccount = v
+	return t
+}
+
+// GetOrgCloudAccount returns attribute or error
+func (t *CloudOperatorConfig) GetOrgCloudAccount() (string, error) {
+	var err error
+	if t.OrgCloudAccount == "" {
+		err = fmt.Errorf("attribute OrgCloudAccount (env var %s) is required", OrgCloudAccountEnv)
+	}
+	return t.OrgCloudAccount, err
+}
+
+// AddOps adds type(s) and returns self
+func (t *CloudOperatorConfig) AddOps(v ...Op) *CloudOperatorConfig {
+	t.Ops = append(t.Ops, v...)
+	return t
+}
+
+// HasOp returns true if type is present in parent
+func (t *CloudOperatorConfig) HasOp(op Op) bool {
+	for _, v := range t.Ops {
+		if v == op {
+			return true
+		}
+	}
+	return false
+}
+
+// SetKubeConcurrency sets attribute and returns self
+func (t *CloudOperatorConfig) SetKubeConcurrency(v int) *CloudOperatorConfig {
+	t.KubeConcurrency = v
+	return t
+}
+
+// GetKubeConcurrency returns attribute. If attribute is not set DefaultKubeConcurrency
+// is returned.
+func (t *CloudOperatorConfig) GetKubeConcurrency() int {
+	if t.KubeConcurrency <= 0 {
+		return DefaultKubeConcurrency
+	}
+	return t.KubeConcurrency
+}
+
+// SetKubeClusterTimeout sets attribute (seconds) and returns self
+func (t *CloudOperatorConfig) SetKubeClusterTimeout(v int) *CloudOperatorConfig {
+	t.KubeClusterTimeout = v
+	return t
+}
+
+// GetKubeClusterTimeout returns attribute as a duration. If attribute is not set
+// DefaultKubeClusterTimeout is returned.
+func (t *CloudOperatorConfig) GetKubeClusterTimeout() time.Duration {
+	if t.KubeClusterTimeout <= 0 {
+		return time.Duration(DefaultKubeClusterTimeout) * time.Second
+	}
+	return time.Duration(t.KubeClusterTimeout) * time.Second
+}
+
+// SetKubeRolloutTimeout sets attribute (seconds) and returns self
+func (t *CloudOperatorConfig) SetKubeRolloutTimeout(v int) *CloudOperatorConfig {
+	t.KubeRolloutTimeout = v
+	return t
+}
+
+// GetKubeRolloutTimeout returns attribute as a duration. If attribute is not set
+// DefaultKubeRolloutTimeout is returned.
+func (t *CloudOperatorConfig) GetKubeRolloutTimeout() time.Duration {
+	if t.KubeRolloutTimeout <= 0 {
+		return time.Duration(DefaultKubeRolloutTimeout) * time.Second
+	}
+	return time.Duration(t.KubeRolloutTimeout) * time.Second
+}
+
+// SetKubeDriftMode sets type and returns self
+func (t *CloudOperatorConfig) SetKubeDriftMode(v DriftMode) *CloudOperatorConfig {
+	t.KubeDriftMode = v
+	return t
+}
+
+// GetKubeDriftMode returns type. If type is not set DriftModeOff is returned.
+func (t *CloudOperatorConfig) GetKubeDriftMode() DriftMode {
+	if t.KubeDriftMode == "" {
+		return DriftModeOff
+	}
+	return t.KubeDriftMode
+}
+
+// SetKubeExportDir sets attribute and returns self
+func (t *CloudOperatorConfig) SetKubeExportDir(v string) *CloudOperatorConfig {
+	t.KubeExportDir = v
+	return t
+}
+
+// SetKubeExportFormat sets type and returns self
+func (t *CloudOperatorConfig) SetKubeExportFormat(v ExportFormat) *CloudOperatorConfig {
+	t.KubeExportFormat = v
+	return t
+}
+
+// GetKubeExportFormat returns type. If type is not set ExportFormatYAML is returned.
+func (t *CloudOperatorConfig) GetKubeExportFormat() ExportFormat {
+	if t.KubeExportFormat == "" {
+		return ExportFormatYAML
+	}
+	return t.KubeExportFormat
+}
+
+// AddKubeExportMatchNames adds attribute(s) and returns self
+func (t *CloudOperatorConfig) AddKubeExportMatchNames(v ...string) *CloudOperatorConfig {
+	t.KubeExportMatchNames = append(t.KubeExportMatchNames, v...)
+	return t
+}
+
+// AddKubeExportMatchTag adds tag and returns self
+func (t *CloudOperatorConfig) AddKubeExportMatchTag(key, value string) *CloudOperatorConfig {
+	if t.KubeExportMatchTags == nil {
+		t.KubeExportMatchTags = make(map[string]string)
+	}
+	t.KubeExportMatchTags[key] = value
+	return t
+}
+
+// MatchKubeExport returns true if the enforcer objects of the cluster are exported rather
+// than applied or error if a name pattern is invalid
+func (t *CloudOperatorConfig) MatchKubeExport(name string, tags map[string]string) (bool, error) {
+
+	if t.KubeExportDir == "" {
+		return false, nil
+	}
+
+	if len(t.KubeExportMatchNames) == 0 && len(t.KubeExportMatchTags) == 0 {
+		return true, nil
+	}
+
+	return matchCluster(t.KubeExportMatchNames, t.KubeExportMatchTags, name, tags)
+}
+
+// SetKubeWorkloadAuthRole sets attribute and returns self
+func (t *CloudOperatorConfig) SetKubeWorkloadAuthRole(v string) *CloudOperatorConfig {
+	t.KubeWorkloadAuthRole = v
+	return t
+}
+
+// GetKubeWorkloadAuthRole returns attribute or DefaultKubeWorkloadAuthRole if not set
+func (t *CloudOperatorConfig) GetKubeWorkloadAuthRole() string {
+	if t.KubeWorkloadAuthRole == "" {
+		return DefaultKubeWorkloadAuthRole
+	}
+	return t.KubeWorkloadAuthRole
+}
+
+// SetNamespaceDeleteGracePeriod sets attribute (seconds) and returns self
+func (t *CloudOperatorConfig) SetNamespaceDeleteGracePeriod(v int) *CloudOperatorConfig {
+	t.NamespaceDeleteGracePeriod = v
+	return t
+}
+
+// GetNamespaceDeleteGracePeriod returns attribute as a duration. If neither the grace period
+// nor the grace runs are set DefaultNamespaceDeleteGracePeriod is returned. If only the grace
+// runs are set zero is returned.
+func (t *CloudOperatorConfig) GetNamespaceDeleteGracePeriod() time.Duration {
+	if t.NamespaceDeleteGracePeriod <= 0 {
+		if t.NamespaceDeleteGraceRuns > 0 {
+			return 0
+		}
+		return time.Duration(DefaultNamespaceDeleteGracePeriod) * time.Second
+	}
+	return time.Duration(t.NamespaceDeleteGracePeriod) * time.Second
+}
+
+// SetNamespaceDeleteGraceRuns sets attribute and returns self
+func (t *CloudOperatorConfig) SetNamespaceDeleteGraceRuns(v int) *CloudOperatorConfig {
+	t.NamespaceDeleteGraceRuns = v
+	return t
+}
+
+// GetNamespaceDeleteGraceRuns returns attribute. Zero means the number of runs is not used.
+func (t *CloudOperatorConfig) GetNamespaceDeleteGraceRuns() int {
+	if t.NamespaceDeleteGraceRuns <= 0 {
+		return 0
+	}
+	return t.NamespaceDeleteGraceRuns
+}
+
+// SetNamespaceDeleteMaxCount sets attribute and returns self
+func (t *CloudOperatorConfig) SetNamespaceDeleteMaxCount(v int) *CloudOperatorConfig {
+	t.NamespaceDeleteMaxCount = v
+	return t
+}
+
+// GetNamespaceDeleteMaxCount returns attribute. Zero means no limit.
+func (t *CloudOperatorConfig) GetNamespaceDeleteMaxCount() int {
+	if t.NamespaceDeleteMaxCount <= 0 {
+		return 0
+	}
+	return t.NamespaceDeleteMaxCount
+}
+
+// SetNamespaceDeleteMaxPercent sets attribute and returns self
+func (t *CloudOperatorConfig) SetNamespaceDeleteMaxPercent(v int) *CloudOperatorConfig {
+	t.NamespaceDeleteMaxPercent = v
+	return t
+}
+
+// GetNamespaceDeleteMaxPercent returns attribute. If attribute is not set
+// DefaultNamespaceDeleteMaxPercent is returned.
+func (t *CloudOperatorConfig) GetNamespaceDeleteMaxPercent() int {
+	if t.NamespaceDeleteMaxPercent <= 0 {
+		return DefaultNamespaceDeleteMaxPercent
+	}
+	return t.NamespaceDeleteMaxPercent
+}
+
+// SetNamespaceCreatePatterns sets entity and returns self
+func (t *CloudOperatorConfig) SetNamespaceCreatePatterns(v NamespacePatterns) *CloudOperatorConfig {
+	t.NamespaceCreatePatterns = v
+	return t
+}
+
+// SetNamespaceDeletePatterns sets entity and returns self
+func (t *CloudOperatorConfig) SetNamespaceDeletePatterns(v NamespacePatterns) *CloudOperatorConfig {
+	t.NamespaceDeletePatterns = v
+	return t
+}
+
+// SetNamespaceLayout sets type and returns self
+func (t *CloudOperatorConfig) SetNamespaceLayout(v NamespaceLayoutType) *CloudOperatorConfig {
+	t.NamespaceLayout = v
+	return t
+}
+
+// GetNamespaceLayout returns type. If type is not set NamespaceLayoutFlat is returned.
+func (t *CloudOperatorConfig) GetNamespaceLayout() NamespaceLayoutType {
+	if t.NamespaceLayout == "" {
+		return NamespaceLayoutFlat
+	}
+	return t.NamespaceLayout
+}
+
+// SetNamespaceTemplates sets templates and returns self
+func (t *CloudOperatorConfig) SetNamespaceTemplates(v NamespaceTemplates) *CloudOperatorConfig {
+	t.NamespaceTemplates = v
+	return t
+}
+
+// AddNamespaceTagKeys adds key pattern(s) and returns self
+func (t *CloudOperatorConfig) AddNamespaceTagKeys(v ...string) *CloudOperatorConfig {
+	t.NamespaceTagKeys = append(t.NamespaceTagKeys, v...)
+	return t
+}
+
+// SetNamespaceTagPrefix sets prefix and returns self
+func (t *CloudOperatorConfig) SetNamespaceTagPrefix(v string) *CloudOperatorConfig {
+	t.NamespaceTagPrefix = v
+	return t
+}
+
+// GetNamespaceTagPrefix returns prefix. If prefix is not set DefaultNamespaceTagPrefix is returned.
+func (t *CloudOperatorConfig) GetNamespaceTagPrefix() string {
+	if t.NamespaceTagPrefix == "" {
+		return DefaultNamespaceTagPrefix
+	}
+	return t.NamespaceTagPrefix
+}
+
+// MatchNamespaceTagKey returns true if key matches a NamespaceTagKeys pattern or error if
+// a pattern is invalid
+func (t *CloudOperatorConfig) MatchNamespaceTagKey(key string) (bool, error) {
+
+	for _, pattern := range t.NamespaceTagKeys {
+		match, err := matchPattern(pattern, key)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ================================================================================================
+
+// NamespaceTemplates are Go templates (text/template) used to name namespaces. The template
+// is executed with the NamespaceEntity so .Name, .Type, .Region and .Network are available.
+// If a template is not set the entity name is used. The result is sanitized to the characters
+// and length allowed by Prisma.
+type NamespaceTemplates struct {
+	Compute string `json:"compute,omitempty" yaml:"compute,omitempty"`
+	Kube    string `json:"kube,omitempty" yaml:"kube,omitempty"`
+}
+
+// ================================================================================================
+
+// NamespaceEntity is a cloud entity (role/service account or cluster) that owns a namespace.
+// The attributes are used by the namespace layout to determine where the namespace is placed.
+type NamespaceEntity struct {
+	Type    CloudEntityType `json:"type" yaml:"type"`
+	Name    string          `json:"name" yaml:"name"`
+	Region  string          `json:"region,omitempty" yaml:"region,omitempty"`
+	Network string          `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// Tags are the cloud tags or labels of the entity. Compute entities aggregate the tags
+	// of their instances so a key may have more than one value.
+	Tags map[string][]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// NewNamespaceEntity returns new entity instance
+func NewNamespaceEntity(ptype CloudEntityType, name string) *NamespaceEntity {
+	return &NamespaceEntity{
+		Type: ptype,
+		Name: name,
+	}
+}
+
+// SetRegion sets attribute and returns self
+func (t *NamespaceEntity) SetRegion(v string) *NamespaceEntity {
+	t.Region = v
+	return t
+}
+
+// SetNetwork sets attribute and returns self
+func (t *NamespaceEntity) SetNetwork(v string) *NamespaceEntity {
+	t.Network = v
+	return t
+}
+
+// AddTag adds the tag value if not already present and returns self
+func (t *NamespaceEntity) AddTag(key, value string) *NamespaceEntity {
+
+	if t.Tags == nil {
+		t.Tags = make(map[string][]string)
+	}
+
+	for _, v := range t.Tags[key] {
+		if v == value {
+			return t
+		}
+	}
+
+	t.Tags[key] = append(t.Tags[key], value)
+	return t
+}
+
+// Equal returns true if v refers to the same entity (tags are not compared)
+func (t *NamespaceEntity) Equal(v *NamespaceEntity) bool {
+	return t.Type == v.Type && t.Name == v.Name && t.Region == v.Region && t.Network == v.Network
+}
+
+// ================================================================================================
+
+// NamespacePatterns is an allowlist (Include) and denylist (Exclude) of namespace names.
+// Patterns are globs (for example eks-*) unless prefixed with "regex:" in which case the
+// remainder is a regular expression that must match the whole name. A name matches if it
+// matches no Exclude pattern and either Include is empty or it matches an Include pattern.
+type NamespacePatterns struct {
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// AddInclude adds pattern(s) and returns self
+func (t *NamespacePatterns) AddInclude(v ...string) *NamespacePatterns {
+	t.Include = append(t.Include, v...)
+	return t
+}
+
+// AddExclude adds pattern(s) and returns self
+func (t *NamespacePatterns) AddExclude(v ...string) *NamespacePatterns {
+	t.Exclude = append(t.Exclude, v...)
+	return t
+}
+
+// Validate returns an error if any pattern is invalid
+func (t *NamespacePatterns) Validate() error {
+
+	var errors *multierror.Error
+
+	for _, pattern := range append(append([]string{}, t.Include...), t.Exclude...) {
+		_, err := matchPattern(pattern, "")
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// Match returns true if name is allowed by the patterns or an error if a pattern is invalid
+func (t *NamespacePatterns) Match(name string) (bool, error) {
+
+	for _, pattern := range t.Exclude {
+		match, err := matchPattern(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return false, nil
+		}
+	}
+
+	if len(t.Include) == 0 {
+		return true, nil
+	}
+
+	for _, pattern := range t.Include {
+		match, err := matchPattern(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func matchPattern(pattern, name string) (bool, error) {
+
+	if strings.HasPrefix(pattern, regexPatternPrefix) {
+		re, err := regexp.Compile("^(?:" + strings.TrimPrefix(pattern, regexPatternPrefix) + ")$")
+		if err != nil {
+			return false, fmt.Errorf("pattern %s is not a valid regular expression: %w", pattern, err)
+		}
+		return re.MatchString(name), nil
+	}
+
+	match, err := path.Match(pattern, name)
+	if err != nil {
+		return false, fmt.Errorf("pattern %s is not a valid glob: %w", pattern, err)
+	}
+
+	return match, nil
+}
+
+// SetEnforcer sets entity and returns self
+func (t *CloudOperatorConfig) SetEnforcer(v EnforcerSpec) *CloudOperatorConfig {
+	t.Enforcer = v
+	return t
+}
+
+// AddEnforcerOverrides adds entity(s) and returns self
+func (t *CloudOperatorConfig) AddEnforcerOverrides(v ...*EnforcerOverride) *CloudOperatorConfig {
+	t.EnforcerOverrides = append(t.EnforcerOverrides, v...)
+	return t
+}
+
+// GetEnforcerSpec returns Enforcer with the matching EnforcerOverrides for the cluster
+// overlaid in order or error if an override name pattern is invalid
+func (t *CloudOperatorConfig) GetEnforcerSpec(name string, tags map[string]string) (*EnforcerSpec, error) {
+
+	result := t.Enforcer.Copy()
+
+	for _, override := range t.EnforcerOverrides {
+
+		match, err := override.Match(name, tags)
+		if err != nil {
+			return nil, err
+		}
+
+		if match {
+			result.Overlay(&override.Spec)
+		}
+	}
+
+	return result, nil
+}
+
+// ================================================================================================
+
+// EnforcerSpec customizes the enforcer DaemonSet built for a cluster. Unset attributes leave
+// the built DaemonSet unchanged.
+type EnforcerSpec struct {
+
+	// Tolerations are added to the pod tolerations
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty" yaml:"tolerations,omitempty"`
+
+	// NodeSelector is merged into the pod node selector
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" yaml:"nodeSelector,omitempty"`
+
+	// Resources replaces the resources of every container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty" yaml:"resources,omitempty"`
+
+	// ImageRegistry replaces the registry of every container image (for example a mirror
+	// such as registry.example.com/mirror)
+	ImageRegistry string `json:"imageRegistry,omitempty" yaml:"imageRegistry,omitempty"`
+
+	// ImagePullSecrets are added to the pod image pull secrets
+	ImagePullSecrets []string `json:"imagePullSecrets,omitempty" yaml:"imagePullSecrets,omitempty"`
+
+	// PriorityClassName sets the pod priority class
+	PriorityClassName string `json:"priorityClassName,omitempty" yaml:"priorityClassName,omitempty"`
+}
+
+// Copy returns a copy of self
+func (t *EnforcerSpec) Copy() *EnforcerSpec {
+
+	result := &EnforcerSpec{
+		Tolerations:       append([]corev1.Toleration{}, t.Tolerations...),
+		ImageRegistry:     t.ImageRegistry,
+		ImagePullSecrets:  append([]string{}, t.ImagePullSecrets...),
+		PriorityClassName: t.PriorityClassName,
+	}
+
+	if t.NodeSelector != nil {
+		result.NodeSelector = make(map[string]string)
+		for k, v := range t.NodeSelector {
+			result.NodeSelector[k] = v
+		}
+	}
+
+	if t.Resources != nil {
+		result.Resources = t.Resources.DeepCopy()
+	}
+
+	return result
+}
+
+// Overlay overlays v onto self and returns self. Lists are appended, maps are merged and
+// set scalars replace.
+func (t *EnforcerSpec) Overlay(v *EnforcerSpec) *EnforcerSpec {
+
+	t.Tolerations = append(t.Tolerations, v.Tolerations...)
+	t.ImagePullSecrets = append(t.ImagePullSecrets, v.ImagePullSecrets...)
+
+	for k, value := range v.NodeSelector {
+		if t.NodeSelector == nil {
+			t.NodeSelector = make(map[string]string)
+		}
+		t.NodeSelector[k] = value
+	}
+
+	if v.Resources != nil {
+		t.Resources = v.Resources.DeepCopy()
+	}
+
+	if v.ImageRegistry != "" {
+		t.ImageRegistry = v.ImageRegistry
+	}
+
+	if v.PriorityClassName != "" {
+		t.PriorityClassName = v.PriorityClassName
+	}
 
-// SetOrgCloudAccount sets attribute and returns self
-func (t *CloudOperatorConfig) SetOrgCloudAccount(v string) *CloudOperatorConfig {
-	t.OrgCloudAccount = v
 	return t
 }
 
-// GetOrgCloudAccount returns attribute or error
-func (t *CloudOperatorConfig) GetOrgCloudAccount() (string, error) {
-	var err error
-	if t.OrgCloudAccount == "" {
-		err = fmt.Errorf("attribute OrgCloudAccount (env var %s) is required", OrgCloudAccountEnv)
-	}
-	return t.OrgCloudAccount, err
+// IsEmpty returns true if the spec changes nothing
+func (t *EnforcerSpec) IsEmpty() bool {
+	return len(t.Tolerations) == 0 && len(t.NodeSelector) == 0 && t.Resources == nil &&
+		t.ImageRegistry == "" && len(t.ImagePullSecrets) == 0 && t.PriorityClassName == ""
 }
 
-// AddOps adds type(s) and returns self
-func (t *CloudOperatorConfig) AddOps(v ...Op) *CloudOperatorConfig {
-	t.Ops = append(t.Ops, v...)
-	return t
+// ================================================================================================
+
+// EnforcerOverride is an EnforcerSpec for the clusters matching any of MatchNames or all
+// of MatchTags. Names are globs or "regex:" patterns.
+type EnforcerOverride struct {
+	MatchNames []string          `json:"matchNames,omitempty" yaml:"matchNames,omitempty"`
+	MatchTags  map[string]string `json:"matchTags,omitempty" yaml:"matchTags,omitempty"`
+	Spec       EnforcerSpec      `json:"spec" yaml:"spec"`
 }
 
-// HasOp returns true if type is present in parent
-func (t *CloudOperatorConfig) HasOp(op Op) bool {
-	for _, v := range t.Ops {
-		if v == op {
-			return true
+// Match returns true if the cluster name or tags match or error if a pattern is invalid
+func (t *EnforcerOverride) Match(name string, tags map[string]string) (bool, error) {
+	return matchCluster(t.MatchNames, t.MatchTags, name, tags)
+}
+
+// matchCluster returns true if name matches any of matchNames or tags contain all of
+// matchTags. An empty matchTags matches nothing.
+func matchCluster(matchNames []string, matchTags map[string]string, name string, tags map[string]string) (bool, error) {
+
+	for _, pattern := range matchNames {
+		match, err := matchPattern(pattern, name)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
 		}
 	}
-	return false
+
+	if len(matchTags) == 0 {
+		return false, nil
+	}
+
+	for k, v := range matchTags {
+		if tags[k] != v {
+			return false, nil
+		}
+	}
+
+	return true, nil
 }
 
 // ================================================================================================
 
-// Filter is a match filter
+// Filter is a match filter. The Kube attributes match clusters. The Compute attributes match
+// compute instances; each attribute that is set must match and an attribute matches if any
+// of its values match. An empty Compute filter matches every instance. A request filter
+// without Kube attributes does not narrow the clusters matched by the config filter.
 type Filter struct {
-	KubeMatchTags  map[string]string `json:"kubeMatchTags" yaml:"kubeMatchTags"`
-	KubeMatchNames []string          `json:"kubeMatchNames" yaml:"kubeMatchNames"`
-	KubeMatchAny   bool              `json:"kubeMatchAny" yaml:"kubeMatchAny"`
+	KubeMatchTags        map[string]string `json:"kubeMatchTags" yaml:"kubeMatchTags"`
+	KubeMatchNames       []string          `json:"kubeMatchNames" yaml:"kubeMatchNames"`
+	KubeMatchAny         bool              `json:"kubeMatchAny" yaml:"kubeMatchAny"`
+	ComputeMatchTags     map[string]string `json:"computeMatchTags,omitempty" yaml:"computeMatchTags,omitempty"`
+	ComputeMatchNetworks []string          `json:"computeMatchNetworks,omitempty" yaml:"computeMatchNetworks,omitempty"`
+	ComputeMatchSubnets  []string          `json:"computeMatchSubnets,omitempty" yaml:"computeMatchSubnets,omitempty"`
+	ComputeMatchAccounts []string          `json:"computeMatchAccounts,omitempty" yaml:"computeMatchAccounts,omitempty"`
+	ComputeMatchStates   []string          `json:"computeMatchStates,omitempty" yaml:"computeMatchStates,omitempty"`
 }
 
 // NewFilter returns new intance of entity
@@ -223,39 +1030,31 @@ func (t *Filter) SetFromEnv() error {
 
 	var errors *multierror.Error
 
-	kubeMatchTagsString := os.Getenv(KubeMatchTagsEnv)
-	kubeMatchNamesString := os.Getenv(KubeMatchNamesEnv)
+	kubeMatchTags, err := getEnvTags(KubeMatchTagsEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
 
-	// Tag format is pairs are delinated by a comma and key/values are delinated by colon
-	// Example: key1:value1,key2:value2,keyN:valueN
-	if kubeMatchTagsString != "" {
+	for key, value := range kubeMatchTags {
+		t.AddKubeMatchTag(key, value)
+	}
 
-		if t.KubeMatchTags == nil {
-			t.KubeMatchTags = make(map[string]string)
-		}
+	t.AddKubeMatchNames(GetEnvList(KubeMatchNamesEnv)...)
 
-		for _, keyValuePair := range strings.Split(kubeMatchTagsString, ",") {
-			keyValuePair = strings.TrimSpace(keyValuePair)
-			keyValuePairSplit := strings.Split(keyValuePair, ":")
-			if len(keyValuePairSplit) != 2 {
-				errors = multierror.Append(errors, fmt.Errorf("keyValuePair %s has invalid syntax. Expected format is key:value", keyValuePair))
-			} else {
-				key := keyValuePairSplit[0]
-				value := keyValuePairSplit[1]
-				t.KubeMatchTags[strings.TrimSpace(key)] = strings.TrimSpace(value)
-			}
-		}
+	computeMatchTags, err := getEnvTags(ComputeMatchTagsEnv)
+	if err != nil {
+		errors = multierror.Append(errors, err)
 	}
 
-	if kubeMatchNamesString != "" {
-		for _, v := range strings.Split(kubeMatchNamesString, ",") {
-			v = strings.TrimSpace(v)
-			if v != "" {
-				t.AddKubeMatchNames(v)
-			}
-		}
+	for key, value := range computeMatchTags {
+		t.AddComputeMatchTag(key, value)
 	}
 
+	t.AddComputeMatchNetworks(GetEnvList(ComputeMatchNetworksEnv)...)
+	t.AddComputeMatchSubnets(GetEnvList(ComputeMatchSubnetsEnv)...)
+	t.AddComputeMatchAccounts(GetEnvList(ComputeMatchAccountsEnv)...)
+	t.AddComputeMatchStates(GetEnvList(ComputeMatchStatesEnv)...)
+
 	kubeMatchAny, err := GetEnvBool(KubeMatchAnyEnv)
 	if err != nil {
 		errors = multierror.Append(errors, err)
@@ -324,6 +1123,173 @@ func (t *Filter) SetKubeMatchAny(v bool) *Filter {
 	return t
 }
 
+// SetComputeMatchTags sets entity and returns self
+func (t *Filter) SetComputeMatchTags(v map[string]string) *Filter {
+	t.ComputeMatchTags = v
+	return t
+}
+
+// AddComputeMatchTag adds attributes (Key/Value tag) and returns self
+func (t *Filter) AddComputeMatchTag(key, value string) *Filter {
+	if t.ComputeMatchTags == nil {
+		t.ComputeMatchTags = make(map[string]string)
+	}
+	t.ComputeMatchTags[key] = value
+	return t
+}
+
+// AddComputeMatchNetworks adds attributes(s) (VPC ID or network name) and returns self
+func (t *Filter) AddComputeMatchNetworks(v ...string) *Filter {
+	t.ComputeMatchNetworks = append(t.ComputeMatchNetworks, v...)
+	return t
+}
+
+// AddComputeMatchSubnets adds attributes(s) (subnet ID or subnetwork name) and returns self
+func (t *Filter) AddComputeMatchSubnets(v ...string) *Filter {
+	t.ComputeMatchSubnets = append(t.ComputeMatchSubnets, v...)
+	return t
+}
+
+// AddComputeMatchAccounts adds attributes(s) (role name or service account email or name)
+// and returns self
+func (t *Filter) AddComputeMatchAccounts(v ...string) *Filter {
+	t.ComputeMatchAccounts = append(t.ComputeMatchAccounts, v...)
+	return t
+}
+
+// AddComputeMatchStates adds attributes(s) (instance state) and returns self
+func (t *Filter) AddComputeMatchStates(v ...string) *Filter {
+	t.ComputeMatchStates = append(t.ComputeMatchStates, v...)
+	return t
+}
+
+// MatchComputeInstance returns true if instance matches the Compute attributes. States are
+// not case sensitive.
+func (t *Filter) MatchComputeInstance(instance *ComputeInstance) bool {
+
+	if !t.MatchComputeInstanceAnyState(instance) {
+		return false
+	}
+
+	if len(t.ComputeMatchStates) > 0 && !matchAny(t.ComputeMatchStates, []string{instance.State}, true) {
+		return false
+	}
+
+	return true
+}
+
+// MatchComputeInstanceAnyState returns true if instance matches the Compute attributes other
+// than the states
+func (t *Filter) MatchComputeInstanceAnyState(instance *ComputeInstance) bool {
+
+	if len(t.ComputeMatchTags) > 0 {
+		match := false
+		for key, value := range instance.Tags {
+			if matchValue, ok := t.ComputeMatchTags[key]; ok && matchValue == value {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+
+	if len(t.ComputeMatchNetworks) > 0 && !matchAny(t.ComputeMatchNetworks, instance.Networks, false) {
+		return false
+	}
+
+	if len(t.ComputeMatchSubnets) > 0 && !matchAny(t.ComputeMatchSubnets, instance.Subnets, false) {
+		return false
+	}
+
+	if len(t.ComputeMatchAccounts) > 0 && !matchAny(t.ComputeMatchAccounts, instance.Accounts, false) {
+		return false
+	}
+
+	return true
+}
+
+// ================================================================================================
+
+// RunInput is the input of a single run. Unlike the CloudOperatorConfig it only applies to the
+// run it is passed to.
+type RunInput struct {
+	// Filter narrows the clusters and compute instances of the run
+	Filter *Filter `json:"filter,omitempty" yaml:"filter,omitempty"`
+
+	// NamespaceDeleteOverride disables the namespace delete maximum count and percentage
+	// checks for the run
+	NamespaceDeleteOverride bool `json:"namespaceDeleteOverride,omitempty" yaml:"namespaceDeleteOverride,omitempty"`
+}
+
+// NewRunInput returns new intance of entity
+func NewRunInput() *RunInput {
+	return &RunInput{}
+}
+
+// SetFilter sets entity and returns self
+func (t *RunInput) SetFilter(v *Filter) *RunInput {
+	t.Filter = v
+	return t
+}
+
+// SetNamespaceDeleteOverride sets attribute and returns self
+func (t *RunInput) SetNamespaceDeleteOverride(v bool) *RunInput {
+	t.NamespaceDeleteOverride = v
+	return t
+}
+
+// ================================================================================================
+
+// ComputeInstance is the compute instance attributes matched by a Filter
+type ComputeInstance struct {
+	Name     string
+	Tags     map[string]string
+	Networks []string
+	Subnets  []string
+	Accounts []string
+	State    string
+}
+
+// NewComputeInstance returns new intance of entity
+func NewComputeInstance(name string) *ComputeInstance {
+	return &ComputeInstance{
+		Name: name,
+		Tags: make(map[string]string),
+	}
+}
+
+// AddTag adds attribute and returns self
+func (t *ComputeInstance) AddTag(key, value string) *ComputeInstance {
+	t.Tags[key] = value
+	return t
+}
+
+// AddNetworks adds attribute(s) and returns self
+func (t *ComputeInstance) AddNetworks(v ...string) *ComputeInstance {
+	t.Networks = append(t.Networks, v...)
+	return t
+}
+
+// AddSubnets adds attribute(s) and returns self
+func (t *ComputeInstance) AddSubnets(v ...string) *ComputeInstance {
+	t.Subnets = append(t.Subnets, v...)
+	return t
+}
+
+// AddAccounts adds attribute(s) and returns self
+func (t *ComputeInstance) AddAccounts(v ...string) *ComputeInstance {
+	t.Accounts = append(t.Accounts, v...)
+	return t
+}
+
+// SetState sets attribute and returns self
+func (t *ComputeInstance) SetState(v string) *ComputeInstance {
+	t.State = v
+	return t
+}
+
 // ================================================================================================
 
 // Report Aggregated Report
@@ -335,6 +1301,7 @@ type Report struct {
 	ErrorCount    int                `json:"errorCount" yaml:"errorCount"`
 	Namespace     *NamespaceReports  `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 	DHCP          *DHCPReport        `json:"dhcp,omitempty" yaml:"dhcp,omitempty"`
+	Infra         *InfraReport       `json:"infra,omitempty" yaml:"infra,omitempty"`
 	Auth          *AuthReport        `json:"auth,omitempty" yaml:"auth,omitempty"`
 	Kubernetes    *KubernetesReports `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
 }
@@ -360,6 +1327,12 @@ func (t *Report) SetDHCP(v *DHCPReport) *Report {
 	return t
 }
 
+// SetInfra set entity and return self
+func (t *Report) SetInfra(v *InfraReport) *Report {
+	t.Infra = v
+	return t
+}
+
 // SetAuth set entity and return self
 func (t *Report) SetAuth(v *AuthReport) *Report {
 	t.Auth = v
@@ -388,6 +1361,13 @@ func (t *Report) Build() *Report {
 		}
 	}
 
+	if t.Infra != nil {
+		t.TotalCount++
+		if t.Infra.Error != nil {
+			t.ErrorCount++
+		}
+	}
+
 	if t.Auth != nil {
 		t.TotalCount++
 		if t.Auth.Error != nil {
@@ -422,6 +1402,12 @@ func (t *Report) Errors() error {
 		}
 	}
 
+	if t.Infra != nil {
+		if t.Infra.Error != nil {
+			errors = multierror.Append(errors, t.Infra.Error)
+		}
+	}
+
 	if t.Auth != nil {
 		if t.Auth.Error != nil {
 			errors = multierror.Append(errors, t.Auth.Error)
@@ -445,6 +1431,7 @@ type NamespaceReports struct {
 	TotalCount int                `json:"totalCount" yaml:"totalCount"`
 	ErrorCount int                `json:"errorCount" yaml:"errorCount"`
 	Namespaces []*NamespaceReport `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Error      error              `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 // NewNamespaceReports returns new entity instance
@@ -458,9 +1445,21 @@ func (t *NamespaceReports) AddNamespaces(v ...*NamespaceReport) *NamespaceReport
 	return t
 }
 
+// SetError sets entity and returns self. This is for errors that apply to the whole
+// operation rather than a single namespace.
+func (t *NamespaceReports) SetError(v error) *NamespaceReports {
+	t.Error = v
+	return t
+}
+
 // Build adds entity(s) and returns self
 func (t *NamespaceReports) Build() *NamespaceReports {
 
+	if t.Error != nil {
+		t.TotalCount++
+		t.ErrorCount++
+	}
+
 	for _, report := range t.Namespaces {
 		t.TotalCount++
 		if report.Error != nil {
@@ -476,6 +1475,10 @@ func (t *NamespaceReports) Errors() error {
 
 	var errors *multierror.Error
 
+	if t.Error != nil {
+		errors = multierror.Append(errors, t.Error)
+	}
+
 	for _, report := range t.Namespaces {
 		if report.Error != nil {
 			errors = multierror.Append(errors, report.Error)
@@ -489,11 +1492,14 @@ func (t *NamespaceReports) Errors() error {
 
 // NamespaceReport Namespace Report
 type NamespaceReport struct {
-	Name      string             `json:"name" yaml:"name"`
-	Type      CloudEntityType    `json:"type" yaml:"type"`
-	Status    OperationStatus    `json:"status" yaml:"status"`
-	Operation NamespaceOperation `json:"operation" yaml:"operation"`
-	Error     error              `json:"error,omitempty" yaml:"error,omitempty"`
+	Name          string             `json:"name" yaml:"name"`
+	Type          CloudEntityType    `json:"type" yaml:"type"`
+	Status        OperationStatus    `json:"status" yaml:"status"`
+	Operation     NamespaceOperation `json:"operation" yaml:"operation"`
+	TombstoneTime int64              `json:"tombstoneTime,omitempty" yaml:"tombstoneTime,omitempty"`
+	TombstoneRuns int                `json:"tombstoneRuns,omitempty" yaml:"tombstoneRuns,omitempty"`
+	Reason        string             `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Error         error              `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 // NewNamespaceReport returns new entity instance
@@ -521,6 +1527,20 @@ func (t *NamespaceReport) SetStatus(v OperationStatus) *NamespaceReport {
 	return t
 }
 
+// SetTombstone sets the time the namespace was first found unused and the number of
+// consecutive runs it has been unused and returns self
+func (t *NamespaceReport) SetTombstone(v time.Time, runs int) *NamespaceReport {
+	t.TombstoneTime = v.Unix()
+	t.TombstoneRuns = runs
+	return t
+}
+
+// SetReason sets attribute and returns self
+func (t *NamespaceReport) SetReason(v string) *NamespaceReport {
+	t.Reason = v
+	return t
+}
+
 // SetError sets entity and returns self
 func (t *NamespaceReport) SetError(v error) *NamespaceReport {
 	t.Error = v
@@ -578,9 +1598,14 @@ func (t *KubernetesReports) Errors() error {
 
 // KubernetesReport is a report for each Kubernetes cluster
 type KubernetesReport struct {
-	Name   string   `json:"name" yaml:"name"`
-	Status OpStatus `json:"status" yaml:"status"`
-	Error  error    `json:"error,omitempty" yaml:"error,omitempty"`
+	Name      string              `json:"name" yaml:"name"`
+	Operation KubernetesOperation `json:"operation,omitempty" yaml:"operation,omitempty"`
+	Status    OpStatus            `json:"status" yaml:"status"`
+	Rollout   *RolloutReport      `json:"rollout,omitempty" yaml:"rollout,omitempty"`
+	Drift     []*DriftReport      `json:"drift,omitempty" yaml:"drift,omitempty"`
+	Export    string              `json:"export,omitempty" yaml:"export,omitempty"`
+	Warnings  []string            `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+	Error     error               `json:"error,omitempty" yaml:"error,omitempty"`
 }
 
 // NewKubernetesReport returns new instance
@@ -590,12 +1615,42 @@ func NewKubernetesReport(v string) *KubernetesReport {
 	}
 }
 
+// SetOperation sets type and returns self
+func (t *KubernetesReport) SetOperation(v KubernetesOperation) *KubernetesReport {
+	t.Operation = v
+	return t
+}
+
 // SetStatus sets type and returns self
 func (t *KubernetesReport) SetStatus(v OpStatus) *KubernetesReport {
 	t.Status = v
 	return t
 }
 
+// SetRollout sets entity and returns self
+func (t *KubernetesReport) SetRollout(v *RolloutReport) *KubernetesReport {
+	t.Rollout = v
+	return t
+}
+
+// AddDrift adds entity(s) and returns self
+func (t *KubernetesReport) AddDrift(v ...*DriftReport) *KubernetesReport {
+	t.Drift = append(t.Drift, v...)
+	return t
+}
+
+// SetExport sets attribute and returns self
+func (t *KubernetesReport) SetExport(v string) *KubernetesReport {
+	t.Export = v
+	return t
+}
+
+// AddWarnings adds attribute(s) and returns self
+func (t *KubernetesReport) AddWarnings(v ...string) *KubernetesReport {
+	t.Warnings = append(t.Warnings, v...)
+	return t
+}
+
 // SetError sets entity and returns self
 func (t *KubernetesReport) SetError(v error) *KubernetesReport {
 	t.Error = v
@@ -604,6 +1659,68 @@ func (t *KubernetesReport) SetError(v error) *KubernetesReport {
 
 // ================================================================================================
 
+// DriftReport lists the fields of a live object that differ from the desired object. Objects
+// owned by another field manager are reported as unmanaged and are not compared.
+type DriftReport struct {
+	Kind        string      `json:"kind" yaml:"kind"`
+	Name        string      `json:"name" yaml:"name"`
+	Status      DriftStatus `json:"status" yaml:"status"`
+	Differences []string    `json:"differences,omitempty" yaml:"differences,omitempty"`
+}
+
+// NewDriftReport returns new instance
+func NewDriftReport(kind, name string, status DriftStatus) *DriftReport {
+	return &DriftReport{
+		Kind:   kind,
+		Name:   name,
+		Status: status,
+	}
+}
+
+// AddDifferences adds attribute(s) and returns self
+func (t *DriftReport) AddDifferences(v ...string) *DriftReport {
+	t.Differences = append(t.Differences, v...)
+	return t
+}
+
+// ================================================================================================
+
+// RolloutReport is the health of the enforcer DaemonSet after it was applied
+type RolloutReport struct {
+	Status  RolloutStatus `json:"status" yaml:"status"`
+	Desired int32         `json:"desired" yaml:"desired"`
+	Ready   int32         `json:"ready" yaml:"ready"`
+	Updated int32         `json:"updated" yaml:"updated"`
+	Reasons []string      `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+}
+
+// NewRolloutReport returns new instance
+func NewRolloutReport() *RolloutReport {
+	return &RolloutReport{}
+}
+
+// SetStatus sets type and returns self
+func (t *RolloutReport) SetStatus(v RolloutStatus) *RolloutReport {
+	t.Status = v
+	return t
+}
+
+// SetCounts sets the node counts and returns self
+func (t *RolloutReport) SetCounts(desired, ready, updated int32) *RolloutReport {
+	t.Desired = desired
+	t.Ready = ready
+	t.Updated = updated
+	return t
+}
+
+// SetReasons sets attribute and returns self
+func (t *RolloutReport) SetReasons(v []string) *RolloutReport {
+	t.Reasons = v
+	return t
+}
+
+// ================================================================================================
+
 // DHCPReport DHCP Report
 type DHCPReport struct {
 	Status OpStatus `json:"status" yaml:"status"`
@@ -629,6 +1746,31 @@ func (t *DHCPReport) SetError(err error) *DHCPReport {
 
 // ================================================================================================
 
+// InfraReport Cloud Infrastructure Services Report
+type InfraReport struct {
+	Status OpStatus `json:"status" yaml:"status"`
+	Error  error    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// NewInfraReport returns new instance
+func NewInfraReport() *InfraReport {
+	return &InfraReport{}
+}
+
+// SetStatus sets type and returns self
+func (t *InfraReport) SetStatus(status OpStatus) *InfraReport {
+	t.Status = status
+	return t
+}
+
+// SetError sets entity and returns self
+func (t *InfraReport) SetError(err error) *InfraReport {
+	t.Error = err
+	return t
+}
+
+// ================================================================================================
+
 // AuthReport Auth Report
 type AuthReport struct {
 	Status OpStatus `json:"status" yaml:"status"`
@@ -672,4 +1814,98 @@ func NewExampleFilterMatchAny() *Filter {
 	return NewFilter().SetKubeMatchAny(true)
 }
 
+// NewExampleFilterMatchCompute returns new example Filter
+func NewExampleFilterMatchCompute() *Filter {
+	return NewFilter().
+		SetKubeMatchAny(true).
+		AddComputeMatchTag("key1", "value1").
+		AddComputeMatchNetworks("network1").
+		AddComputeMatchStates("running")
+}
+
+// ================================================================================================
+
+// PreflightReport is the result of the permission checks for the enabled ops
+type PreflightReport struct {
+	CloudProvider string            `json:"cloudProvider" yaml:"cloudProvider"`
+	RunTime       int64             `json:"runTime" yaml:"runTime"`
+	Status        PreflightStatus   `json:"status" yaml:"status"`
+	TotalCount    int               `json:"totalCount" yaml:"totalCount"`
+	FailCount     int               `json:"failCount" yaml:"failCount"`
+	Checks        []*PreflightCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// NewPreflightReport returns new instance
+func NewPreflightReport(cloudProvider string) *PreflightReport {
+	return &PreflightReport{
+		CloudProvider: cloudProvider,
+		RunTime:       time.Now().Unix(),
+	}
+}
+
+// AddChecks adds entity(s) and returns self
+func (t *PreflightReport) AddChecks(v ...*PreflightCheck) *PreflightReport {
+	t.Checks = append(t.Checks, v...)
+	return t
+}
+
+// Build sets counts and status and returns self. The status is PreflightStatusFail if any
+// check failed.
+func (t *PreflightReport) Build() *PreflightReport {
+
+	t.TotalCount = len(t.Checks)
+	t.FailCount = 0
+
+	for _, check := range t.Checks {
+		if check.Status == PreflightStatusFail {
+			t.FailCount++
+		}
+	}
+
+	t.Status = PreflightStatusPass
+	if t.FailCount > 0 {
+		t.Status = PreflightStatusFail
+	}
+
+	return t
+}
+
 // ================================================================================================
+
+// PreflightCheck is a single permission check. Op is empty for checks required by every op.
+// Target is the cloud provider, Prisma namespace or cluster the check was run against.
+type PreflightCheck struct {
+	Op      Op              `json:"op,omitempty" yaml:"op,omitempty"`
+	Target  string          `json:"target" yaml:"target"`
+	Check   string          `json:"check" yaml:"check"`
+	Status  PreflightStatus `json:"status" yaml:"status"`
+	Message string          `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+// NewPreflightCheck returns new instance
+func NewPreflightCheck(op Op, target, check string) *PreflightCheck {
+	return &PreflightCheck{
+		Op:     op,
+		Target: target,
+		Check:  check,
+	}
+}
+
+// SetError sets status PreflightStatusPass if v is nil otherwise PreflightStatusFail with
+// the error as message and returns self
+func (t *PreflightCheck) SetError(v error) *PreflightCheck {
+	if v == nil {
+		t.Status = PreflightStatusPass
+		return t
+	}
+	t.Status = PreflightStatusFail
+	t.Message = v.Error()
+	return t
+}
+
+// SetSkip sets status PreflightStatusSkip with reason as message and returns self
+func (t *PreflightCheck) SetSkip(reason string) *PreflightCheck {
+	t.Status = PreflightStatusSkip
+	t.Message = reason
+	return t
+}
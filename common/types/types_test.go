@@ -0,0 +1,162 @@
+package types
+
+import (
+	"testing"
+)
+
+func TestFilterMatchComputeInstance(t *testing.T) {
+
+	instance := NewComputeInstance("instance").
+		AddTag("env", "prod").
+		AddNetworks("network1").
+		AddSubnets("subnet1", "subnet2").
+		AddAccounts("account1").
+		SetState("RUNNING")
+
+	tests := map[string]struct {
+		filter *Filter
+		want   bool
+	}{
+		"empty":               {filter: NewFilter(), want: true},
+		"kube only":           {filter: NewExampleFilterMatchAny(), want: true},
+		"tag":                 {filter: NewFilter().AddComputeMatchTag("env", "prod"), want: true},
+		"tag any":             {filter: NewFilter().AddComputeMatchTag("env", "dev").AddComputeMatchTag("env2", "prod"), want: false},
+		"tag value mismatch":  {filter: NewFilter().AddComputeMatchTag("env", "dev"), want: false},
+		"network":             {filter: NewFilter().AddComputeMatchNetworks("network2", "network1"), want: true},
+		"network mismatch":    {filter: NewFilter().AddComputeMatchNetworks("network2"), want: false},
+		"subnet":              {filter: NewFilter().AddComputeMatchSubnets("subnet2"), want: true},
+		"subnet mismatch":     {filter: NewFilter().AddComputeMatchSubnets("subnet3"), want: false},
+		"account":             {filter: NewFilter().AddComputeMatchAccounts("account1"), want: true},
+		"account mismatch":    {filter: NewFilter().AddComputeMatchAccounts("account2"), want: false},
+		"state ignores case":  {filter: NewFilter().AddComputeMatchStates("running"), want: true},
+		"state mismatch":      {filter: NewFilter().AddComputeMatchStates("stopped"), want: false},
+		"all attributes":      {filter: NewExampleFilterMatchCompute().AddComputeMatchSubnets("subnet1").AddComputeMatchAccounts("account1").SetComputeMatchTags(map[string]string{"env": "prod"}), want: true},
+		"one attribute fails": {filter: NewFilter().AddComputeMatchNetworks("network1").AddComputeMatchStates("stopped"), want: false},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := test.filter.MatchComputeInstance(instance); got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNamespacePatternsMatch(t *testing.T) {
+
+	tests := map[string]struct {
+		patterns *NamespacePatterns
+		name     string
+		want     bool
+		wantErr  bool
+	}{
+		"empty matches all": {
+			patterns: &NamespacePatterns{},
+			name:     "eks-prod",
+			want:     true,
+		},
+		"empty matches empty name": {
+			patterns: &NamespacePatterns{},
+			name:     "",
+			want:     true,
+		},
+		"include glob": {
+			patterns: (&NamespacePatterns{}).AddInclude("eks-*"),
+			name:     "eks-prod",
+			want:     true,
+		},
+		"include glob mismatch": {
+			patterns: (&NamespacePatterns{}).AddInclude("eks-*"),
+			name:     "gke-prod",
+			want:     false,
+		},
+		"include any": {
+			patterns: (&NamespacePatterns{}).AddInclude("gke-*", "eks-*"),
+			name:     "eks-prod",
+			want:     true,
+		},
+		"exclude glob": {
+			patterns: (&NamespacePatterns{}).AddExclude("*-test"),
+			name:     "eks-test",
+			want:     false,
+		},
+		"exclude glob mismatch": {
+			patterns: (&NamespacePatterns{}).AddExclude("*-test"),
+			name:     "eks-prod",
+			want:     true,
+		},
+		"exclude beats include": {
+			patterns: (&NamespacePatterns{}).AddInclude("eks-*").AddExclude("eks-test"),
+			name:     "eks-test",
+			want:     false,
+		},
+		"regex include": {
+			patterns: (&NamespacePatterns{}).AddInclude("regex:eks-(prod|dev)"),
+			name:     "eks-dev",
+			want:     true,
+		},
+		"regex matches whole name": {
+			patterns: (&NamespacePatterns{}).AddInclude("regex:eks-(prod|dev)"),
+			name:     "eks-devel",
+			want:     false,
+		},
+		"regex exclude beats include": {
+			patterns: (&NamespacePatterns{}).AddInclude("*").AddExclude("regex:.*-test"),
+			name:     "eks-test",
+			want:     false,
+		},
+		"bad regex": {
+			patterns: (&NamespacePatterns{}).AddInclude("regex:eks-("),
+			name:     "eks-prod",
+			wantErr:  true,
+		},
+		"bad regex in exclude": {
+			patterns: (&NamespacePatterns{}).AddInclude("eks-*").AddExclude("regex:[a-"),
+			name:     "eks-prod",
+			wantErr:  true,
+		},
+		"bad glob": {
+			patterns: (&NamespacePatterns{}).AddInclude("eks-[a-"),
+			name:     "eks-prod",
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			got, err := test.patterns.Match(test.name)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNamespacePatternsValidate(t *testing.T) {
+
+	tests := map[string]struct {
+		patterns *NamespacePatterns
+		wantErr  bool
+	}{
+		"empty":          {patterns: &NamespacePatterns{}},
+		"valid":          {patterns: (&NamespacePatterns{}).AddInclude("eks-*", "regex:gke-.*").AddExclude("*-test")},
+		"bad regex":      {patterns: (&NamespacePatterns{}).AddInclude("regex:eks-("), wantErr: true},
+		"bad exclude":    {patterns: (&NamespacePatterns{}).AddExclude("regex:*"), wantErr: true},
+		"bad glob":       {patterns: (&NamespacePatterns{}).AddInclude("eks-[a-"), wantErr: true},
+		"one bad of two": {patterns: (&NamespacePatterns{}).AddInclude("eks-*", "regex:("), wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := test.patterns.Validate(); (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -2,8 +2,13 @@ package types
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"sigs.k8s.io/yaml"
 )
 
 // GetEnvBool returns bool value from env if var is set to valid bool.
@@ -28,3 +33,92 @@ func GetEnvBool(env string) (bool, error) {
 
 	return false, fmt.Errorf("env variable %s is invalid. It should be either true or false", env)
 }
+
+// GetEnvInt returns int value from env if var is set to valid int.
+// If var is not set then 0 will be returned. An error will only be
+// returned if the env var is not a type int.
+func GetEnvInt(env string) (int, error) {
+
+	s := os.Getenv(env)
+	if s == "" {
+		return 0, nil
+	}
+
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("env variable %s is invalid. It should be an integer", env)
+	}
+
+	return v, nil
+}
+
+// GetEnvList returns the comma separated values of env with surrounding
+// spaces and empty values removed. If var is not set then nil will be returned.
+func GetEnvList(env string) []string {
+
+	var result []string
+
+	for _, v := range strings.Split(os.Getenv(env), ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// getEnvTags returns the tags of env. Pairs are delinated by a comma and key/values are
+// delinated by colon. Example: key1:value1,key2:value2,keyN:valueN
+func getEnvTags(env string) (map[string]string, error) {
+
+	var errors *multierror.Error
+
+	result := make(map[string]string)
+
+	for _, keyValuePair := range GetEnvList(env) {
+		keyValuePairSplit := strings.Split(keyValuePair, ":")
+		if len(keyValuePairSplit) != 2 {
+			errors = multierror.Append(errors, fmt.Errorf("keyValuePair %s has invalid syntax. Expected format is key:value", keyValuePair))
+		} else {
+			result[strings.TrimSpace(keyValuePairSplit[0])] = strings.TrimSpace(keyValuePairSplit[1])
+		}
+	}
+
+	return result, errors.ErrorOrNil()
+}
+
+// matchAny returns true if any of values is in matches
+func matchAny(matches, values []string, ignoreCase bool) bool {
+	for _, match := range matches {
+		for _, value := range values {
+			if match == value || (ignoreCase && strings.EqualFold(match, value)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadConfigFile reads the YAML or JSON file named by the env variable ConfigFileEnv
+// into v. If the env variable is not set nothing is done. This should be called before
+// SetFromEnv so that env variables take precedence.
+func LoadConfigFile(v interface{}) error {
+
+	filename := os.Getenv(ConfigFileEnv)
+	if filename == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("unable to read config file %s (env var %s): %w", filename, ConfigFileEnv, err)
+	}
+
+	err = yaml.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("unable to parse config file %s (env var %s): %w", filename, ConfigFileEnv, err)
+	}
+
+	return nil
+}
@@ -3,7 +3,7 @@
 function main() {
   cd "$(dirname "$0")"
 
-  out=$(./linux-amd64) || {
+  out=$(./linux-amd64 "$@") || {
     err "Failed"
     return 3
   }
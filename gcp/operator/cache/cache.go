@@ -18,6 +18,8 @@ type Cache struct {
 	Instances       []*Instance
 	Clusters        []*Cluster
 	ServiceAccounts []*ServiceAccount
+	Networks        []*Network
+	Subnetworks     []*Subnetwork
 }
 
 func (t *Cache) init(ctx context.Context) error {
@@ -52,9 +54,31 @@ func (t *Cache) init(ctx context.Context) error {
 		return err
 	}
 
-	createdByToInstanceMap := make(map[string]*Instance)
+	networks, subnetworks, err := t.listNetworks(ctx, gcp)
+	if err != nil {
+		zap.L().Debug("returning init with error(s)")
+		return err
+	}
+
+	selfLinkToSubnetworkMap := make(map[string]*Subnetwork)
+	for _, subnetwork := range subnetworks {
+		selfLinkToSubnetworkMap[subnetwork.SelfLink] = subnetwork
+	}
+
+	selfLinkToInstanceMap := make(map[string]*Instance)
 	emailToServiceAccountMap := make(map[string]*ServiceAccount)
 
+	// serviceAccount returns the cached service account for email creating it if required
+	serviceAccount := func(gcpServiceAccount *gcp_compute.ServiceAccount) *ServiceAccount {
+		result := emailToServiceAccountMap[gcpServiceAccount.Email]
+		if result == nil {
+			result = newServiceAccount(gcpServiceAccount)
+			serviceAccounts = append(serviceAccounts, result)
+			emailToServiceAccountMap[gcpServiceAccount.Email] = result
+		}
+		return result
+	}
+
 	for _, gcpInstance := range gcpInstances.Items {
 
 		// A new instance is always created as each iteration will be a new instance
@@ -86,63 +110,94 @@ func (t *Cache) init(ctx context.Context) error {
 		for _, gcpServiceAccount := range gcpInstance.ServiceAccounts {
 
 			// Because Service Accounts can be assigned to multiple instances the
-			// service account may already exist. Hence we use a map and key on the
-			// service account email which is unique. If the service account exist we
-			// fetch it and update it. If not we create it new and store it in the map.
-
-			serviceAccount := emailToServiceAccountMap[gcpServiceAccount.Email]
-
-			if serviceAccount == nil {
-				serviceAccount = newServiceAccount(gcpServiceAccount)
-			}
+			// service account may already exist. It is keyed on the email which is unique.
+			account := serviceAccount(gcpServiceAccount)
 
 			// A Service Account may be assigned to instances that are both part of a Kubernetes cluster
 			// and normal instances. So we add the Instance to either the Kuberbetes Instances or the Compute
 			// Instances. We only do one per iteration.
 			if isKubernetes {
-				serviceAccount.KubernetesInstances = append(serviceAccount.KubernetesInstances, instance)
+				account.KubernetesInstances = append(account.KubernetesInstances, instance)
 			} else {
-				serviceAccount.ComputeInstances = append(serviceAccount.ComputeInstances, instance)
+				account.ComputeInstances = append(account.ComputeInstances, instance)
 			}
 
-			// We add the instance to the service account, the service account to the instance, the
-			// service account to the service account slice and service account map
-
-			serviceAccounts = append(serviceAccounts, serviceAccount)
-			instance.ServiceAccounts = append(instance.ServiceAccounts, serviceAccount)
-			emailToServiceAccountMap[gcpServiceAccount.Email] = serviceAccount
+			instance.ServiceAccounts = append(instance.ServiceAccounts, account)
 		}
 
-		// We store the instance in the instances slice and if the instance has the CreatedBy attribute set
-		// then we store it in a map. This will be used when we iterate the clusters to map the cluster to its
-		// instance(s) and the reverse
-		instances = append(instances, instance)
-
-		if instance.CreatedBy != "" {
-			createdByToInstanceMap[instance.CreatedBy] = instance
+		// Each network interface of the instance is attached to a subnetwork of the region
+		for _, networkInterface := range gcpInstance.NetworkInterfaces {
+			subnetwork := selfLinkToSubnetworkMap[networkInterface.Subnetwork]
+			if subnetwork != nil {
+				subnetwork.Instances = append(subnetwork.Instances, instance)
+				instance.Subnetworks = append(instance.Subnetworks, subnetwork)
+			}
 		}
 
+		// The instance groups of the node pools list their members by self link
+		instances = append(instances, instance)
+		selfLinkToInstanceMap[instance.SelfLink] = instance
 	}
 
+	// The default compute service account is only looked up if a node pool uses it
+	defaultServiceAccount := ""
+
 	for _, gkeCluster := range gcpClusters.Clusters {
 
 		// Each iteration is a unique cluster so we create a new cluster wrapper
 		cluster := newCluster(gkeCluster)
 
-		// We use the cluster InstanceGroups to find the cluster's instances (if any) by looking the instance up
-		// with the CreatedBy attribute
-		for _, instanceGroupURL := range cluster.InstanceGroupUrls {
-			cluster.CreatedBy = basename(instanceGroupURL)
-			instance := createdByToInstanceMap[cluster.CreatedBy]
-			if instance != nil {
-				// If the instance exist then we add the instance to the cluster and the cluster to the instance
-				cluster.Instances = append(cluster.Instances, instance)
-				instance.Clusters = append(instance.Clusters, cluster)
-
-				if instance.ServiceAccounts != nil {
-					cluster.ServiceAccounts = append(cluster.ServiceAccounts, instance.ServiceAccounts...)
+		// The service accounts are read from the node pool config so that they are known
+		// before any node is running
+		for _, nodePool := range cluster.NodePools {
+
+			email := ""
+			if nodePool.Config != nil {
+				email = nodePool.Config.ServiceAccount
+			}
+
+			if email == "" || email == "default" {
+
+				if defaultServiceAccount == "" {
+					project, err := gcp.Projects.Get(t.project).Context(ctx).Do()
+					if err != nil {
+						zap.L().Debug("returning init with error(s)")
+						return err
+					}
+					defaultServiceAccount = project.DefaultServiceAccount
 				}
 
+				email = defaultServiceAccount
+			}
+
+			account := serviceAccount(&gcp_compute.ServiceAccount{Email: email})
+			cluster.addServiceAccount(account)
+			account.addCluster(cluster)
+
+			// The members of the node pool instance groups are the cluster nodes. Only the
+			// instances of the zone are cached.
+			for _, instanceGroupURL := range nodePool.InstanceGroupUrls {
+
+				zone, name := instanceGroupZoneName(instanceGroupURL)
+				if zone != t.zone {
+					continue
+				}
+
+				err := gcp.InstanceGroups.ListInstances(t.project, zone, name, &gcp_compute.InstanceGroupsListInstancesRequest{}).
+					Pages(ctx, func(page *gcp_compute.InstanceGroupsListInstances) error {
+						for _, item := range page.Items {
+							instance := selfLinkToInstanceMap[item.Instance]
+							if instance != nil {
+								cluster.Instances = append(cluster.Instances, instance)
+								instance.Clusters = append(instance.Clusters, cluster)
+							}
+						}
+						return nil
+					})
+				if err != nil {
+					zap.L().Debug("returning init with error(s)")
+					return err
+				}
 			}
 		}
 
@@ -154,11 +209,74 @@ func (t *Cache) init(ctx context.Context) error {
 	t.Instances = instances
 	t.Clusters = clusters
 	t.ServiceAccounts = serviceAccounts
+	t.Networks = networks
+	t.Subnetworks = subnetworks
 
 	return nil
 }
 
+// listNetworks returns the VPC networks of the project and the subnetworks of the zone region
+// including their secondary ranges
+func (t *Cache) listNetworks(ctx context.Context, gcp *gcp_compute.Service) ([]*Network, []*Subnetwork, error) {
+
+	var networks []*Network
+	var subnetworks []*Subnetwork
+
+	selfLinkToNetworkMap := make(map[string]*Network)
+
+	err := gcp.Networks.List(t.project).Pages(ctx, func(page *gcp_compute.NetworkList) error {
+		for _, gcpNetwork := range page.Items {
+			network := newNetwork(gcpNetwork)
+			networks = append(networks, network)
+			selfLinkToNetworkMap[network.SelfLink] = network
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = gcp.Subnetworks.List(t.project, ZoneRegion(t.zone)).Pages(ctx, func(page *gcp_compute.SubnetworkList) error {
+		for _, gcpSubnetwork := range page.Items {
+			subnetwork := newSubnetwork(gcpSubnetwork)
+			subnetworks = append(subnetworks, subnetwork)
+			network := selfLinkToNetworkMap[subnetwork.Network]
+			if network != nil {
+				subnetwork.VpcNetwork = network
+				network.Subnetworks = append(network.Subnetworks, subnetwork)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return networks, subnetworks, nil
+}
+
 func basename(input string) string {
 	x := strings.Split(input, "/")
 	return x[len(x)-1]
 }
+
+// instanceGroupZoneName returns the zone and name of an instance group or instance group
+// manager URL
+func instanceGroupZoneName(url string) (string, string) {
+	x := strings.Split(url, "/")
+	for i := 0; i < len(x)-1; i++ {
+		if x[i] == "zones" {
+			return x[i+1], x[len(x)-1]
+		}
+	}
+	return "", x[len(x)-1]
+}
+
+// ZoneRegion returns the region of zone, for example us-central1 for us-central1-a
+func ZoneRegion(zone string) string {
+	i := strings.LastIndex(zone, "-")
+	if i < 0 {
+		return zone
+	}
+	return zone[:i]
+}
@@ -9,7 +9,6 @@ type Cluster struct {
 	*gke_service.Cluster
 	Instances       []*Instance
 	ServiceAccounts []*ServiceAccount
-	CreatedBy       string
 }
 
 func newCluster(cluster *gke_service.Cluster) *Cluster {
@@ -17,3 +16,50 @@ func newCluster(cluster *gke_service.Cluster) *Cluster {
 		Cluster: cluster,
 	}
 }
+
+func (t *Cluster) addServiceAccount(serviceAccount *ServiceAccount) {
+	for _, existing := range t.ServiceAccounts {
+		if existing == serviceAccount {
+			return
+		}
+	}
+	t.ServiceAccounts = append(t.ServiceAccounts, serviceAccount)
+}
+
+// PodCidrBlocks returns the CIDR block pods are assigned from
+func (t *Cluster) PodCidrBlocks() []string {
+
+	if t.IpAllocationPolicy != nil && t.IpAllocationPolicy.ClusterIpv4CidrBlock != "" {
+		return []string{t.IpAllocationPolicy.ClusterIpv4CidrBlock}
+	}
+
+	if t.ClusterIpv4Cidr != "" {
+		return []string{t.ClusterIpv4Cidr}
+	}
+
+	return nil
+}
+
+// ServiceCidrBlocks returns the CIDR block services are assigned from
+func (t *Cluster) ServiceCidrBlocks() []string {
+
+	if t.IpAllocationPolicy != nil && t.IpAllocationPolicy.ServicesIpv4CidrBlock != "" {
+		return []string{t.IpAllocationPolicy.ServicesIpv4CidrBlock}
+	}
+
+	if t.ServicesIpv4Cidr != "" {
+		return []string{t.ServicesIpv4Cidr}
+	}
+
+	return nil
+}
+
+// NodeCidrBlocks returns the CIDR block nodes are assigned from if known
+func (t *Cluster) NodeCidrBlocks() []string {
+
+	if t.IpAllocationPolicy != nil && t.IpAllocationPolicy.NodeIpv4CidrBlock != "" {
+		return []string{t.IpAllocationPolicy.NodeIpv4CidrBlock}
+	}
+
+	return nil
+}
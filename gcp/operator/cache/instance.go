@@ -10,6 +10,7 @@ type Instance struct {
 	ServiceAccounts []*ServiceAccount
 	CreatedBy       string
 	Clusters        []*Cluster
+	Subnetworks     []*Subnetwork
 }
 
 func newInstance(instance *gcp_compute.Instance) *Instance {
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	gcp_compute "google.golang.org/api/compute/v1"
+)
+
+// Network GCP VPC network wrapper
+type Network struct {
+	*gcp_compute.Network
+	Subnetworks []*Subnetwork
+}
+
+func newNetwork(network *gcp_compute.Network) *Network {
+	return &Network{
+		Network: network,
+	}
+}
+
+// Subnetwork GCP subnetwork wrapper. Only the subnetworks of the zone region are cached.
+type Subnetwork struct {
+	*gcp_compute.Subnetwork
+	VpcNetwork *Network
+	Instances  []*Instance
+}
+
+func newSubnetwork(subnetwork *gcp_compute.Subnetwork) *Subnetwork {
+	return &Subnetwork{
+		Subnetwork: subnetwork,
+	}
+}
+
+// CidrBlocks returns the primary and secondary CIDR blocks of the subnetwork
+func (t *Subnetwork) CidrBlocks() []string {
+
+	var result []string
+
+	if t.IpCidrRange != "" {
+		result = append(result, t.IpCidrRange)
+	}
+
+	for _, secondaryRange := range t.SecondaryIpRanges {
+		if secondaryRange.IpCidrRange != "" {
+			result = append(result, secondaryRange.IpCidrRange)
+		}
+	}
+
+	return result
+}
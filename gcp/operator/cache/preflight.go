@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	gcp_compute "google.golang.org/api/compute/v1"
+	gke_service "google.golang.org/api/container/v1"
+
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const preflightTarget = "gcp"
+
+// Preflight verifies the permissions used by Build and the clients and returns a check per call
+// and enabled op. Only list and get calls are made.
+func (t *Config) Preflight(ctx context.Context, ops []lib_types.Op) []*lib_types.PreflightCheck {
+
+	zap.L().Debug("entering Preflight")
+
+	if t.Project == "" || t.Zone == "" {
+		zap.L().Debug("returning Preflight; project and zone are required")
+		return []*lib_types.PreflightCheck{
+			lib_types.NewPreflightCheck("", preflightTarget, "configuration").SetError(fmt.Errorf("project and zone are required")),
+		}
+	}
+
+	// A service that can not be created fails every probe that uses it
+	gcp, gcpErr := gcp_compute.NewService(ctx)
+	gke, gkeErr := gke_service.NewService(ctx)
+
+	// The get call is made against the first cluster found by the list call. The table lists
+	// it after the list call.
+	var cluster string
+
+	probes := map[string]preflightProbe{
+
+		"compute.instances.list": func() (string, error) {
+			if gcpErr != nil {
+				return "", gcpErr
+			}
+			_, err := gcp.Instances.List(t.Project, t.Zone).MaxResults(1).Context(ctx).Do()
+			return "", err
+		},
+
+		"compute.networks.list": func() (string, error) {
+			if gcpErr != nil {
+				return "", gcpErr
+			}
+			_, err := gcp.Networks.List(t.Project).MaxResults(1).Context(ctx).Do()
+			return "", err
+		},
+
+		"compute.subnetworks.list": func() (string, error) {
+			if gcpErr != nil {
+				return "", gcpErr
+			}
+			_, err := gcp.Subnetworks.List(t.Project, ZoneRegion(t.Zone)).MaxResults(1).Context(ctx).Do()
+			return "", err
+		},
+
+		"compute.projects.get": func() (string, error) {
+			if gcpErr != nil {
+				return "", gcpErr
+			}
+			_, err := gcp.Projects.Get(t.Project).Context(ctx).Do()
+			return "", err
+		},
+
+		"compute.instanceGroups.list": func() (string, error) {
+			if gcpErr != nil {
+				return "", gcpErr
+			}
+			_, err := gcp.InstanceGroups.List(t.Project, t.Zone).MaxResults(1).Context(ctx).Do()
+			return "", err
+		},
+
+		"container.clusters.list": func() (string, error) {
+			if gkeErr != nil {
+				return "", gkeErr
+			}
+			out, err := gke.Projects.Zones.Clusters.List(t.Project, t.Zone).Context(ctx).Do()
+			if err == nil && len(out.Clusters) > 0 {
+				cluster = out.Clusters[0].Name
+			}
+			return "", err
+		},
+
+		"container.clusters.get": func() (string, error) {
+			if gkeErr != nil {
+				return "", gkeErr
+			}
+			if cluster == "" {
+				return "no cluster to check against", nil
+			}
+			_, err := gke.Projects.Zones.Clusters.Get(t.Project, t.Zone, cluster).Context(ctx).Do()
+			return "", err
+		},
+	}
+
+	result := runPreflightProbes(permissions.Calls(permissions.ProviderGCP, ops), ops, probes)
+
+	zap.L().Debug("returning Preflight")
+	return result
+}
+
+// preflightProbe makes the call for one permission. It returns a reason if the call can not be made.
+type preflightProbe func() (string, error)
+
+// runPreflightProbes runs the probe of every call in order and returns its result for each
+// enabled op making the call. A call without a probe fails so the checks can not drift from the
+// permissions table.
+func runPreflightProbes(calls []*permissions.Call, ops []lib_types.Op, probes map[string]preflightProbe) []*lib_types.PreflightCheck {
+
+	var result []*lib_types.PreflightCheck
+
+	for _, call := range calls {
+
+		var skip string
+		var err error
+
+		probe, ok := probes[call.Permission]
+		if ok {
+			skip, err = probe()
+		} else {
+			err = fmt.Errorf("no preflight probe for %s", call.Permission)
+		}
+
+		for _, op := range call.EnabledOps(ops) {
+
+			check := lib_types.NewPreflightCheck(op, preflightTarget, call.Permission)
+
+			if skip != "" {
+				result = append(result, check.SetSkip(skip))
+				continue
+			}
+
+			result = append(result, check.SetError(err))
+		}
+	}
+
+	return result
+}
@@ -12,6 +12,7 @@ type ServiceAccount struct {
 	NamespaceName       string
 	ComputeInstances    []*Instance
 	KubernetesInstances []*Instance
+	Clusters            []*Cluster
 }
 
 func newServiceAccount(serviceAccount *gcp_compute.ServiceAccount) *ServiceAccount {
@@ -29,7 +30,20 @@ func (t *ServiceAccount) ComputeInstancesLen() int {
 	return len(t.ComputeInstances)
 }
 
-// ClustersLen returns length of KubernetesInstances
+// ClustersLen returns length of Clusters. Nodes of clusters outside the zone are not in
+// Clusters so length of KubernetesInstances is returned if there are none.
 func (t *ServiceAccount) ClustersLen() int {
+	if len(t.Clusters) > 0 {
+		return len(t.Clusters)
+	}
 	return len(t.KubernetesInstances)
 }
+
+func (t *ServiceAccount) addCluster(cluster *Cluster) {
+	for _, existing := range t.Clusters {
+		if existing == cluster {
+			return
+		}
+	}
+	t.Clusters = append(t.Clusters, cluster)
+}
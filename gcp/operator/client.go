@@ -3,14 +3,15 @@ package operator
 import (
 	"context"
 	"fmt"
-	"sync"
+	"path"
 
 	builder "github.com/aporeto-se/enforcerd-kube-builder"
 	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
-	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
 	"github.com/hashicorp/go-multierror"
 	"go.uber.org/zap"
 
+	"github.com/aporeto-se/cloud-operator/common/auth"
+	"github.com/aporeto-se/cloud-operator/common/layout"
 	"github.com/aporeto-se/cloud-operator/common/processors"
 	"github.com/aporeto-se/cloud-operator/common/reportwrapper"
 	"github.com/aporeto-se/cloud-operator/common/tag"
@@ -27,10 +28,13 @@ type Client struct {
 	cloudOperatorConfig      *types.CloudOperatorConfig
 	api                      string
 	accountID                string
+	project                  string
 	protectConfig            bool
 	orgTenant                string
 	orgCloudAccount          string
 	namespace                string
+	region                   string
+	layout                   layout.NamespaceLayout
 }
 
 // NewClient returns new Client or error
@@ -77,6 +81,11 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		errors = multierror.Append(errors, err)
 	}
 
+	namespaceLayout, err := layout.NewNamespaceLayout(&config.CloudOperatorConfig.CloudOperatorConfig)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
 	protectConfig := true
 	if config.CloudOperatorConfig.DisableProtectConfig {
 		protectConfig = false
@@ -95,6 +104,9 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		return nil, err
 	}
 
+	// The cluster region fallback is the region of the zone, not the zone itself
+	region := cache.ZoneRegion(zone)
+
 	cache, err := cache.NewConfig().SetProject(project).SetZone(zone).Build(ctx)
 	if err != nil {
 		zap.L().Debug("returning Build with error(s)")
@@ -112,22 +124,166 @@ func NewClient(ctx context.Context, config *Config) (*Client, error) {
 		cloudAccountPrismaClient: config.PrismaClient,
 		cloudOperatorConfig:      config.CloudOperatorConfig,
 		accountID:                accountID,
+		project:                  project,
 		api:                      api,
 		protectConfig:            protectConfig,
 		orgTenant:                orgTenant,
 		orgCloudAccount:          orgCloudAccount,
 		namespace:                namespace,
+		region:                   region,
+		layout:                   namespaceLayout,
 		Cache:                    cache,
 	}, nil
 
 }
 
-// Run run and returns report. Any error(s) will be wrapped in report.
-func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.Report {
+// computeEntity returns the namespace entity for the compute instances of account
+func (t *Client) computeEntity(account *cache.ServiceAccount, instances []*cache.Instance) *lib_types.NamespaceEntity {
+
+	entity := lib_types.NewNamespaceEntity(lib_types.CloudEntityTypeCompute, account.NamespaceName).
+		SetRegion(t.region)
+
+	// The labels of every matched instance using the service account are aggregated
+	for _, instance := range instances {
+		for key, value := range instance.Labels {
+			entity.AddTag(key, value)
+		}
+	}
+
+	return entity
+}
+
+// computeEntities returns the compute namespace entity of each service account with instances
+// in scope of the config filter. The request filter is not applied so the namespaces of the
+// accounts it does not select are not deleted.
+func (t *Client) computeEntities(tagMatcher *tag.Matcher) []*lib_types.NamespaceEntity {
+
+	var result []*lib_types.NamespaceEntity
+
+	for _, account := range t.ServiceAccounts {
+		instances := t.computeInstances(account, tagMatcher.InScopeComputeInstance)
+		if len(instances) > 0 {
+			result = append(result, t.computeEntity(account, instances))
+			zap.L().Debug(fmt.Sprintf("compute namespace %s added to add list", account.NamespaceName))
+		} else {
+			zap.L().Debug(fmt.Sprintf("Service Account %s has NO matching compute instances", account.Email))
+		}
+	}
+
+	return result
+}
+
+// computeInstances returns the compute instances of account matched by match. Networks and
+// subnetworks are matched by name.
+func (t *Client) computeInstances(account *cache.ServiceAccount, match func(*lib_types.ComputeInstance) bool) []*cache.Instance {
+
+	var result []*cache.Instance
+
+	for _, instance := range account.ComputeInstances {
+
+		computeInstance := lib_types.NewComputeInstance(instance.Name).
+			AddAccounts(account.Email, account.NamespaceName).
+			SetState(instance.Status)
+
+		for key, value := range instance.Labels {
+			computeInstance.AddTag(key, value)
+		}
+
+		// Legacy networks have no subnetworks
+		for _, networkInterface := range instance.NetworkInterfaces {
+			computeInstance.AddNetworks(path.Base(networkInterface.Network))
+			if networkInterface.Subnetwork != "" {
+				computeInstance.AddSubnets(path.Base(networkInterface.Subnetwork))
+			}
+		}
+
+		if match(computeInstance) {
+			result = append(result, instance)
+		} else {
+			zap.L().Debug(fmt.Sprintf("instance %s does NOT match filter", instance.Name))
+		}
+	}
+
+	return result
+}
+
+// computeAuthInstances returns the compute instances of account matched by tagMatcher and
+// true if account is authorized by COMPUTE_AUTH
+func (t *Client) computeAuthInstances(account *cache.ServiceAccount, tagMatcher *tag.Matcher) ([]*cache.Instance, bool) {
+
+	instances := t.computeInstances(account, tagMatcher.MatchComputeInstance)
+
+	if len(instances) > 0 {
+		// If the service account has any matching compute instances we add it
+		return instances, true
+	}
+
+	if account.ComputeInstancesLen() > 0 {
+		return nil, false
+	}
+
+	// If the service account has NO Kubernetes instances we add it but if it does have
+	// Kubernetes instances (and no compute) then we do NOT add it. An account without
+	// instances is matched by name only so any other compute filter excludes it.
+	if account.ClustersLen() > 0 {
+		return nil, false
+	}
+
+	return nil, tagMatcher.MatchComputeInstance(lib_types.NewComputeInstance("").AddAccounts(account.Email, account.NamespaceName))
+}
+
+// conflicts returns an error keyed by the namespace path of each compute and kube entity
+// that collides with another entity. Nothing is written to these paths.
+func (t *Client) conflicts(tagMatcher *tag.Matcher) map[string]error {
+
+	var entities []*lib_types.NamespaceEntity
+
+	for _, account := range t.ServiceAccounts {
+		if instances, add := t.computeAuthInstances(account, tagMatcher); add {
+			entities = append(entities, t.computeEntity(account, instances))
+		}
+	}
+
+	for _, cluster := range t.Clusters {
+		entities = append(entities, t.kubeEntity(cluster))
+	}
+
+	return layout.Conflicts(t.layout, entities...)
+}
+
+// kubeEntity returns the namespace entity for cluster
+func (t *Client) kubeEntity(cluster *cache.Cluster) *lib_types.NamespaceEntity {
+
+	region := cluster.Location
+	if region == "" {
+		region = t.region
+	}
+
+	entity := lib_types.NewNamespaceEntity(lib_types.CloudEntityTypeKubernetes, cluster.Name).
+		SetRegion(region).
+		SetNetwork(cluster.Network)
+
+	for key, value := range cluster.ResourceLabels {
+		entity.AddTag(key, value)
+	}
+
+	return entity
+}
+
+// Run run and returns report. Any error(s) will be wrapped in report. The input only applies
+// to this run and may be nil.
+func (t *Client) Run(ctx context.Context, input *lib_types.RunInput) *lib_types.Report {
 
 	zap.L().Debug("entering Run")
 
-	tagMatcher, _ := tag.NewMatcher(&t.cloudOperatorConfig.Filter, filter)
+	if input == nil {
+		input = lib_types.NewRunInput()
+	}
+
+	tagMatcher, _ := tag.NewMatcher(&t.cloudOperatorConfig.Filter, input.Filter)
+
+	// Entities whose namespaces collide are skipped by every op that writes to the namespace
+	conflicts := t.conflicts(tagMatcher)
 
 	report := lib_types.NewReport(cloudProvider)
 
@@ -138,6 +294,13 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 		zap.L().Debug("DHCP operation is disabled")
 	}
 
+	// Infrastructure Services
+	if t.cloudOperatorConfig.HasOp(lib_types.OpInfraServices) {
+		report.SetInfra(t.infraReport(ctx))
+	} else {
+		zap.L().Debug("Infrastructure Services operation is disabled")
+	}
+
 	// The Namespace and Auth options are present for both Compute and Kubernetes. If either Compute or Kubernetes
 	// has the operation set we will run it. The subfunction will determine if it is to be ran for Compute, Kubernetes
 	// or both.
@@ -147,27 +310,28 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceComputeCreate) ||
 		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceComputeDelete) ||
 		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceKubeCreate) ||
-		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceKubeDelete) {
+		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceKubeDelete) ||
+		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceMigrateNames) {
 
 		zap.L().Debug("Namespace operation is enabled")
 
-		nsprocessor, _ := processors.NewNamespaceProcessor(&t.cloudOperatorConfig.CloudOperatorConfig, t.cloudAccountPrismaClient)
+		nsprocessor, err := processors.NewNamespaceProcessor(&t.cloudOperatorConfig.CloudOperatorConfig, t.cloudAccountPrismaClient)
+		if err != nil {
+			report.SetNamespace(lib_types.NewNamespaceReports().SetError(err))
+		} else {
+			nsprocessor.SetDeleteOverride(input.NamespaceDeleteOverride)
 
-		for _, account := range t.ServiceAccounts {
-			if account.ComputeInstancesLen() > 0 {
-				nsprocessor.AddCompute(account.NamespaceName)
-				zap.L().Debug(fmt.Sprintf("compute namespace %s added to add list", account.NamespaceName))
-			} else {
-				zap.L().Debug(fmt.Sprintf("Service Account %s has NO compute instances", account.Email))
+			for _, entity := range t.computeEntities(tagMatcher) {
+				nsprocessor.AddCompute(entity)
 			}
-		}
 
-		for _, cluster := range t.Clusters {
-			nsprocessor.AddKube(cluster.Name)
-			zap.L().Debug(fmt.Sprintf("kubernetes namespace %s added to add list", cluster.Name))
-		}
+			for _, cluster := range t.Clusters {
+				nsprocessor.AddKube(t.kubeEntity(cluster))
+				zap.L().Debug(fmt.Sprintf("kubernetes namespace %s added to add list", cluster.Name))
+			}
 
-		report.SetNamespace(nsprocessor.Process(ctx))
+			report.SetNamespace(nsprocessor.Process(ctx))
+		}
 
 	} else {
 		zap.L().Debug("Namespace operation is disabled")
@@ -175,7 +339,7 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 
 	// DHCP is neither a Compute or Kubernetes op
 	if t.cloudOperatorConfig.HasOp(lib_types.OpComputeAuth) || t.cloudOperatorConfig.HasOp(lib_types.OpKubeAuth) {
-		report.SetAuth(t.authReport(ctx))
+		report.SetAuth(t.authReport(ctx, tagMatcher, conflicts))
 	} else {
 		zap.L().Debug("Auth operation is disabled")
 	}
@@ -195,6 +359,9 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 		case lib_types.OpKubeAuth:
 			runKube = true
 
+		case lib_types.OpKubeWorkloadAuth:
+			runKube = true
+
 		case lib_types.OpKubeAPINet:
 			runKube = true
 
@@ -204,15 +371,21 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 		case lib_types.OpKubeNodesNet:
 			runKube = true
 
+		case lib_types.OpKubeClusterNets:
+			runKube = true
+
 		case lib_types.OpKubeEnforcer:
 			runKube = true
 
+		case lib_types.OpKubeEnforcerUninstall:
+			runKube = true
+
 		}
 
 	}
 
 	if runKube {
-		report.SetKubernetes(t.kubernetesReports(ctx, tagMatcher))
+		report.SetKubernetes(t.kubernetesReports(ctx, tagMatcher, conflicts))
 	} else {
 		zap.L().Debug("Kubernetes operations are disabled")
 	}
@@ -221,105 +394,38 @@ func (t *Client) Run(ctx context.Context, filter *lib_types.Filter) *lib_types.R
 	return report.Build()
 }
 
-func (t *Client) dhcpReport(ctx context.Context) *lib_types.DHCPReport {
-
-	zap.L().Debug("entering dhcpReport")
-
-	report := lib_types.NewDHCPReport().
-		SetStatus(lib_types.OpStatusFailed)
-
-	prismaConfig := prisma_types.NewPrismaConfig(dhcpImportLabel)
-
-	prismaConfig.AddExternalnetwork(
-		prisma_types.NewExternalnetwork("GCP DHCP").
-			SetDescription("auto-generated by Cloud Operator").
-			SetProtected(t.protectConfig).
-			SetPropagate(true).
-			AddEntry("169.254.169.254"))
-
-	egressRule := prisma_types.NewRule().
-		SetTrafficActionAllow().
-		AddUDPProtocolPort(67).
-		AddUDPProtocolPort(68).
-		AddObject(
-			"@org:cloudaccount="+t.orgCloudAccount,
-			"@org:tenant="+t.orgTenant,
-			"externalnetwork:name=GCP DHCP")
-
-	prismaConfig.AddNetworkrulesetpolicy(
-		prisma_types.NewNetworkrulesetpolicy("GCP DHCP Linux").
-			SetDescription("auto-generated by Cloud Operator").
-			AddOutgoingRule(egressRule).
-			AddSubject(
-				"@org:cloudaccount="+t.orgCloudAccount,
-				"@org:tenant="+t.orgTenant,
-				"@os:host=linux",
-			).
-			SetProtected(t.protectConfig).
-			SetPropagate(true))
-
-	prismaConfig.AddNetworkrulesetpolicy(
-		prisma_types.NewNetworkrulesetpolicy("GCP DHCP Windows").
-			SetDescription("auto-generated by Cloud Operator").
-			AddOutgoingRule(egressRule).
-			AddSubject(
-				"@org:cloudaccount="+t.orgCloudAccount,
-				"@org:tenant="+t.orgTenant,
-				"@os:host=windows",
-			).
-			SetProtected(t.protectConfig).
-			SetPropagate(true))
-
-	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", dhcpImportLabel))
-	err := t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
-
-	if err != nil {
-		zap.L().Debug("returning dhcpReport with error(s)")
-		return report.SetError(err)
-	}
-
-	zap.L().Debug("returning dhcpReport")
-	return report.SetStatus(lib_types.OpStatusCompleted)
-}
-
-func (t *Client) authReport(ctx context.Context) *lib_types.AuthReport {
+func (t *Client) authReport(ctx context.Context, tagMatcher *tag.Matcher, conflicts map[string]error) *lib_types.AuthReport {
 
 	report := lib_types.NewAuthReport().
 		SetStatus(lib_types.OpStatusFailed)
 
-	prismaConfig := prisma_types.NewPrismaConfig(authImportLabel)
+	authBuilder := auth.NewBuilder(t.cloudOperatorConfig.Ops).
+		SetProtected(t.protectConfig)
+
+	// Policies are not written to a namespace that conflicts. The remaining policies are
+	// imported and the conflicts are reported.
+	var errors *multierror.Error
 
 	// Auth Policies for Kubernetes
 
-	// OpDHCP OpComputeNamespace OpKubeNamespace OpKubeAuth OpKubeAPINet OpKubeDNSNet OpKubeNodesNet OpKubeEnforcer
+	// OpDHCP OpComputeNamespace OpKubeNamespace OpKubeAuth OpKubeAPINet OpKubeDNSNet OpKubeNodesNet OpKubeClusterNets OpKubeEnforcer
 
 	if t.cloudOperatorConfig.HasOp(lib_types.OpComputeAuth) {
 
 		for _, account := range t.ServiceAccounts {
 
-			add := false
-			if account.ComputeInstancesLen() > 0 {
-				// If the service account has any compute instances we add it
-				add = true
-			}
+			instances, add := t.computeAuthInstances(account, tagMatcher)
+
+			path := t.layout.Path(t.computeEntity(account, instances))
 
-			if !add {
-				// If the service account has NO Kubernetes instances we add it
-				// but if it does have Kubernetes instances (and no compute) then
-				// we do NOT add it.
-				if account.ClustersLen() <= 0 {
-					add = true
-				}
+			if err, ok := conflicts[path]; ok && add {
+				errors = multierror.Append(errors, err)
+				zap.L().Debug(fmt.Sprintf("Service Account %s NOT added to Auth Policy (namespace conflict)", account.Email))
+				continue
 			}
 
 			if add {
-				prismaConfig.AddApiauthorizationpolicy(
-					prisma_types.NewAPIAuthorizationPolicy("instances:"+account.Email).
-						SetDescription("auto-generated cloud operator policy").
-						SetProtected(t.protectConfig).
-						SetAuthorizedNamespace(t.namespace+"/"+account.NamespaceName).
-						AddAuthorizedIdentity("@auth:role=enforcer").
-						AddSubject(realm, accountLabel+t.accountID, role+account.Email))
+				authBuilder.AddCompute(auth.GCPIdentity(t.accountID, account.Email), t.namespace+"/"+path)
 
 				zap.L().Debug(fmt.Sprintf("Service Account %s added to Auth Policy for instance namespace %s", account.Email, account.NamespaceName))
 
@@ -336,14 +442,15 @@ func (t *Client) authReport(ctx context.Context) *lib_types.AuthReport {
 	if t.cloudOperatorConfig.HasOp(lib_types.OpKubeAuth) {
 
 		for _, cluster := range t.Clusters {
+
+			if err, ok := conflicts[t.layout.Path(t.kubeEntity(cluster))]; ok {
+				errors = multierror.Append(errors, err)
+				zap.L().Debug(fmt.Sprintf("Cluster %s NOT added to Auth Policy (namespace conflict)", cluster.Name))
+				continue
+			}
+
 			for _, account := range cluster.ServiceAccounts {
-				prismaConfig.AddApiauthorizationpolicy(
-					prisma_types.NewAPIAuthorizationPolicy(cluster.Name+":"+account.Email).
-						SetDescription("auto-generated cloud operator policy").
-						SetProtected(t.protectConfig).
-						SetAuthorizedNamespace(t.namespace+"/"+cluster.Name).
-						AddAuthorizedIdentity("@auth:role=enforcer").
-						AddSubject(realm, accountLabel+t.accountID, role+account.Email))
+				authBuilder.AddKube(cluster.Name, auth.GCPIdentity(t.accountID, account.Email), t.namespace+"/"+t.layout.Path(t.kubeEntity(cluster)))
 
 				zap.L().Debug(fmt.Sprintf("Service Account %s added to Auth Policy for cluster namespace %s", account.Email, cluster.Name))
 
@@ -355,10 +462,14 @@ func (t *Client) authReport(ctx context.Context) *lib_types.AuthReport {
 	}
 
 	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", authImportLabel))
-	err := t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
+	err := t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, authBuilder.Build(authImportLabel))
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
 
+	err = errors.ErrorOrNil()
 	if err != nil {
-		zap.L().Debug("returning dhcpReport with error(s)")
+		zap.L().Debug("returning authReport with error(s)")
 		return report.SetError(err)
 	}
 
@@ -366,26 +477,41 @@ func (t *Client) authReport(ctx context.Context) *lib_types.AuthReport {
 
 }
 
-func (t *Client) kubernetesReports(ctx context.Context, tagMatcher *tag.Matcher) *lib_types.KubernetesReports {
+func (t *Client) kubernetesReports(ctx context.Context, tagMatcher *tag.Matcher, conflicts map[string]error) *lib_types.KubernetesReports {
 
 	zap.L().Debug("entering kubernetesReports")
 
-	var wg sync.WaitGroup
-
-	wrapper := reportwrapper.NewWrapper()
+	// Clusters are processed concurrently but bounded so that large accounts do not overwhelm
+	// the Prisma API. Each cluster has its own deadline so a hung API server can not stall the run.
+	wrapper := reportwrapper.NewWrapper().
+		SetConcurrency(t.cloudOperatorConfig.GetKubeConcurrency()).
+		SetTimeout(t.cloudOperatorConfig.GetKubeClusterTimeout())
 
 	for _, _cluster := range t.Clusters {
 
 		cluster := _cluster
 
+		if err, ok := conflicts[t.layout.Path(t.kubeEntity(cluster))]; ok {
+			zap.L().Debug(fmt.Sprintf("Cluster %s namespace conflict", cluster.Name))
+			wrapper.AddKube(lib_types.NewKubernetesReport(cluster.Name).
+				SetStatus(lib_types.OpStatusFailed).
+				SetError(err))
+			continue
+		}
+
 		if tagMatcher.MatchKubeCluster(cluster.Name, cluster.ResourceLabels) {
 			zap.L().Debug(fmt.Sprintf("Cluster %s is a match", cluster.Name))
 
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				wrapper.AddKube(t.kubernetesReport(ctx, cluster))
-			}()
+			wrapper.RunKube(ctx, cluster.Name, func(ctx context.Context) *lib_types.KubernetesReport {
+				return t.kubernetesReport(ctx, cluster)
+			})
+
+		} else if t.cloudOperatorConfig.HasOp(lib_types.OpKubeEnforcerUninstall) && !tagMatcher.InScopeKubeCluster(cluster.Name, cluster.ResourceLabels) {
+			zap.L().Debug(fmt.Sprintf("Cluster %s is out of scope; uninstalling", cluster.Name))
+
+			wrapper.RunKube(ctx, cluster.Name, func(ctx context.Context) *lib_types.KubernetesReport {
+				return t.kubernetesUninstallReport(ctx, cluster)
+			})
 
 		} else {
 			zap.L().Debug(fmt.Sprintf("Cluster %s is NOT a match", cluster.Name))
@@ -393,8 +519,6 @@ func (t *Client) kubernetesReports(ctx context.Context, tagMatcher *tag.Matcher)
 
 	}
 
-	wg.Wait()
-
 	zap.L().Debug("returning kubernetesReports")
 	return wrapper.Build()
 }
@@ -404,6 +528,7 @@ func (t *Client) kubernetesReport(ctx context.Context, cluster *cache.Cluster) *
 	zap.L().Debug("entering kubernetesReport")
 
 	report := lib_types.NewKubernetesReport(cluster.Name).
+		SetOperation(lib_types.KubernetesOperationSync).
 		SetStatus(lib_types.OpStatusFailed)
 
 	if cluster.Status != "RUNNING" {
@@ -427,7 +552,7 @@ func (t *Client) kubernetesReport(ctx context.Context, cluster *cache.Cluster) *
 		return report.SetError(err)
 	}
 
-	prismaClient, err := t.cloudAccountPrismaClient.NewClient(ctx, cluster.Name)
+	prismaClient, err := layout.NewClient(ctx, t.cloudAccountPrismaClient, t.layout.Path(t.kubeEntity(cluster)))
 	if err != nil {
 		zap.L().Debug("returning kubernetesReport with wrapped error(s)")
 		return report.SetError(err)
@@ -436,11 +561,18 @@ func (t *Client) kubernetesReport(ctx context.Context, cluster *cache.Cluster) *
 	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, prismaClient)
 	err = kubeprocessor.
 		AddCidrBlocks(cluster.ClusterIpv4Cidr).
+		AddNodeCidrBlocks(cluster.NodeCidrBlocks()...).
+		AddPodCidrBlocks(cluster.PodCidrBlocks()...).
+		AddServiceCidrBlocks(cluster.ServiceCidrBlocks()...).
 		SetKubernetesDaemonsetBuilder(builder.NewGke(prismaClient.GetNamespacePath(), t.api)).
 		SetEndpoint(endpoint).
+		SetTags(cluster.ResourceLabels).
+		SetWorkloadIdentity(auth.GCPWorkloadIdentity(t.project, t.accountID)).
 		SetKubernetesClientset(kubernetesClientset).
 		Process(ctx)
 
+	report.SetRollout(kubeprocessor.Rollout()).AddDrift(kubeprocessor.Drift()...).SetExport(kubeprocessor.Export())
+
 	if err != nil {
 		zap.L().Debug("returning kubernetesReport with wrapped error(s)")
 		return report.SetError(err)
@@ -451,3 +583,66 @@ func (t *Client) kubernetesReport(ctx context.Context, cluster *cache.Cluster) *
 	return report.SetStatus(lib_types.OpStatusCompleted)
 
 }
+
+// kubernetesUninstallReport removes the enforcer and Prisma config from a cluster that is
+// no longer in scope
+func (t *Client) kubernetesUninstallReport(ctx context.Context, cluster *cache.Cluster) *lib_types.KubernetesReport {
+
+	zap.L().Debug("entering kubernetesUninstallReport")
+
+	report := lib_types.NewKubernetesReport(cluster.Name).
+		SetOperation(lib_types.KubernetesOperationUninstall).
+		SetStatus(lib_types.OpStatusFailed)
+
+	if cluster.Status != "RUNNING" {
+		zap.L().Debug("returning kubernetesUninstallReport (not active)")
+		return report.SetStatus(lib_types.OpStatusNotReady)
+	}
+
+	kubernetesClientset, err := getKubernetesClientset(cluster)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with error(s)")
+		return report.SetError(err)
+	}
+
+	path := t.layout.Path(t.kubeEntity(cluster))
+
+	// Without the cluster namespace nothing was ever installed by the operator
+	exists, err := layout.Exists(ctx, t.cloudAccountPrismaClient, path)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	if !exists {
+		zap.L().Debug("returning kubernetesUninstallReport (no namespace)")
+		return report.SetStatus(lib_types.OpStatusNothingToDo)
+	}
+
+	prismaClient, err := layout.NewClient(ctx, t.cloudAccountPrismaClient, path)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, prismaClient)
+
+	removed, err := kubeprocessor.
+		SetKubernetesDaemonsetBuilder(builder.NewGke(prismaClient.GetNamespacePath(), t.api)).
+		SetKubernetesClientset(kubernetesClientset).
+		SetTags(cluster.ResourceLabels).
+		Uninstall(ctx)
+
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning kubernetesUninstallReport")
+
+	if !removed {
+		return report.SetStatus(lib_types.OpStatusNothingToDo)
+	}
+
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
@@ -1,10 +1,10 @@
 package operator
 
 const (
-	cloudProvider   = "Google Cloud Platform"
-	dhcpImportLabel = "Cloud-Operator-DHCP"
-	authImportLabel = "Cloud-Operator-AUTH"
-	accountLabel    = "@auth:projectnumber="
-	realm           = "@auth:realm=gcpidentitytoken"
-	role            = "@auth:email="
+	cloudProvider    = "Google Cloud Platform"
+	dhcpImportLabel  = "Cloud-Operator-DHCP"
+	authImportLabel  = "Cloud-Operator-AUTH"
+	infraImportLabel = "Cloud-Operator-INFRA"
+	networkTag       = "cloud:gcp:network="
+	subnetworkTag    = "cloud:gcp:subnetwork="
 )
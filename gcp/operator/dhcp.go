@@ -0,0 +1,62 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	"go.uber.org/zap"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// dhcpReport imports the DHCP external network and policies. The metadata server is the DHCP
+// server of every subnetwork so the policies are grouped by the DHCPMode.
+func (t *Client) dhcpReport(ctx context.Context) *lib_types.DHCPReport {
+
+	zap.L().Debug("entering dhcpReport")
+
+	report := lib_types.NewDHCPReport().
+		SetStatus(lib_types.OpStatusFailed)
+
+	prismaConfig := prisma_types.NewPrismaConfig(dhcpImportLabel)
+
+	prismaConfig.AddExternalnetwork(
+		prisma_types.NewExternalnetwork("GCP DHCP").
+			SetDescription("auto-generated by Cloud Operator").
+			SetProtected(t.protectConfig).
+			SetPropagate(true).
+			AddEntry("169.254.169.254"))
+
+	egressRule := prisma_types.NewRule().
+		SetTrafficActionAllow().
+		AddUDPProtocolPort(67).
+		AddUDPProtocolPort(68).
+		AddObject(
+			"@org:cloudaccount="+t.orgCloudAccount,
+			"@org:tenant="+t.orgTenant,
+			"externalnetwork:name=GCP DHCP")
+
+	for _, scope := range t.policyScopes("GCP DHCP") {
+		for _, operatingSystem := range operatingSystems {
+			prismaConfig.AddNetworkrulesetpolicy(
+				prisma_types.NewNetworkrulesetpolicy(scope.name + " " + operatingSystem.name).
+					SetDescription("auto-generated by Cloud Operator").
+					AddOutgoingRule(egressRule).
+					AddSubject(t.subject(scope, operatingSystem.tag)...).
+					SetProtected(t.protectConfig).
+					SetPropagate(true))
+		}
+	}
+
+	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", dhcpImportLabel))
+	err := t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
+
+	if err != nil {
+		zap.L().Debug("returning dhcpReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning dhcpReport")
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
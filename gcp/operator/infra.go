@@ -0,0 +1,102 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	prisma_types "github.com/aporeto-se/prisma-sdk-go-v2/types"
+	"go.uber.org/zap"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// infraService is a cloud infrastructure service reached by enforced hosts
+type infraService struct {
+	name     string
+	entries  []string
+	tcpPorts []int
+	udpPorts []int
+}
+
+// infraReport imports an external network and outgoing rule for the metadata server, which
+// also serves DNS and NTP. The policies are grouped by the DHCPMode.
+func (t *Client) infraReport(ctx context.Context) *lib_types.InfraReport {
+
+	zap.L().Debug("entering infraReport")
+
+	report := lib_types.NewInfraReport().
+		SetStatus(lib_types.OpStatusFailed)
+
+	services := []*infraService{
+		{
+			name:     "GCP Metadata",
+			entries:  []string{"169.254.169.254"},
+			tcpPorts: []int{80},
+		},
+		{
+			name:     "GCP DNS",
+			entries:  []string{"169.254.169.254"},
+			tcpPorts: []int{53},
+			udpPorts: []int{53},
+		},
+		{
+			name:     "GCP NTP",
+			entries:  []string{"169.254.169.254"},
+			udpPorts: []int{123},
+		},
+	}
+
+	prismaConfig := prisma_types.NewPrismaConfig(infraImportLabel)
+
+	var rules []*prisma_types.Rule
+
+	for _, service := range services {
+
+		prismaConfig.AddExternalnetwork(
+			prisma_types.NewExternalnetwork(service.name).
+				SetDescription("auto-generated by Cloud Operator").
+				SetProtected(t.protectConfig).
+				SetPropagate(true).
+				AddEntry(service.entries...))
+
+		rule := prisma_types.NewRule().
+			SetTrafficActionAllow().
+			AddObject(
+				"@org:cloudaccount="+t.orgCloudAccount,
+				"@org:tenant="+t.orgTenant,
+				"externalnetwork:name="+service.name)
+
+		for _, port := range service.tcpPorts {
+			rule.AddTCPProtocolPort(port)
+		}
+
+		for _, port := range service.udpPorts {
+			rule.AddUDPProtocolPort(port)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	for _, scope := range t.policyScopes("GCP Infrastructure Services") {
+		for _, operatingSystem := range operatingSystems {
+			prismaConfig.AddNetworkrulesetpolicy(
+				prisma_types.NewNetworkrulesetpolicy(scope.name + " " + operatingSystem.name).
+					SetDescription("auto-generated by Cloud Operator").
+					AddOutgoingRule(rules...).
+					AddSubject(t.subject(scope, operatingSystem.tag)...).
+					SetProtected(t.protectConfig).
+					SetPropagate(true))
+		}
+	}
+
+	zap.L().Debug(fmt.Sprintf("Importing Prisma API config for %s", infraImportLabel))
+	err := t.cloudAccountPrismaClient.ImportPrismaConfig(ctx, prismaConfig)
+
+	if err != nil {
+		zap.L().Debug("returning infraReport with error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning infraReport")
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
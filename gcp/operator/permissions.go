@@ -0,0 +1,15 @@
+package operator
+
+import (
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	"github.com/aporeto-se/cloud-operator/gcp/types"
+)
+
+// Permissions returns the least privilege cloud, Prisma and Kubernetes permissions required by the
+// enabled ops. No API is called.
+func Permissions(cloudOperatorConfig *types.CloudOperatorConfig) *permissions.Manifest {
+
+	namespace, _ := cloudOperatorConfig.GetNamespace()
+
+	return permissions.NewManifest(cloudProvider, permissions.ProviderGCP, namespace, cloudOperatorConfig.Ops)
+}
@@ -0,0 +1,114 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	builder "github.com/aporeto-se/enforcerd-kube-builder"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/common/processors"
+	"github.com/aporeto-se/cloud-operator/common/tag"
+	"github.com/aporeto-se/cloud-operator/gcp/operator/cache"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const prismaPreflightTarget = "prisma"
+
+// Preflight verifies the cloud, Prisma and Kubernetes permissions required by the enabled
+// ops and returns a check per op and target. Unlike NewClient it does not stop at the first
+// missing permission. Nothing is changed.
+func Preflight(ctx context.Context, config *Config, filter *lib_types.Filter) *lib_types.PreflightReport {
+
+	zap.L().Debug("entering Preflight")
+
+	report := lib_types.NewPreflightReport(cloudProvider)
+
+	if config.CloudOperatorConfig == nil || config.PrismaClient == nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "configuration").
+			SetError(fmt.Errorf("entities CloudOperatorConfig and PrismaClient are required"))).Build()
+	}
+
+	project, err := config.CloudOperatorConfig.GetGCloudProject()
+	if err != nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "configuration").SetError(err)).Build()
+	}
+
+	zone, err := config.CloudOperatorConfig.GetGCloudZone()
+	if err != nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "configuration").SetError(err)).Build()
+	}
+
+	report.AddChecks(cache.NewConfig().SetProject(project).SetZone(zone).Preflight(ctx, config.CloudOperatorConfig.Ops)...)
+
+	_, err = config.PrismaClient.AccountID(ctx)
+	report.AddChecks(lib_types.NewPreflightCheck("", prismaPreflightTarget, "read cloud account").SetError(err))
+
+	if report.Build().Status == lib_types.PreflightStatusFail {
+		zap.L().Debug("returning Preflight; cluster checks skipped")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "clusters").
+			SetSkip("cluster checks require the cloud and Prisma checks to pass")).Build()
+	}
+
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "discover resources").SetError(err)).Build()
+	}
+
+	report.AddChecks(client.preflightClusters(ctx, filter)...)
+
+	zap.L().Debug("returning Preflight")
+	return report.Build()
+}
+
+// preflightClusters returns the checks of the clusters that Run would process
+func (t *Client) preflightClusters(ctx context.Context, filter *lib_types.Filter) []*lib_types.PreflightCheck {
+
+	var result []*lib_types.PreflightCheck
+
+	tagMatcher, _ := tag.NewMatcher(&t.cloudOperatorConfig.Filter, filter)
+
+	for _, cluster := range t.Clusters {
+
+		if !tagMatcher.MatchKubeCluster(cluster.Name, cluster.ResourceLabels) && !t.cloudOperatorConfig.HasOp(lib_types.OpKubeEnforcerUninstall) {
+			continue
+		}
+
+		result = append(result, t.preflightCluster(ctx, cluster)...)
+	}
+
+	return result
+}
+
+func (t *Client) preflightCluster(ctx context.Context, cluster *cache.Cluster) []*lib_types.PreflightCheck {
+
+	path := t.layout.Path(t.kubeEntity(cluster))
+
+	result := []*lib_types.PreflightCheck{
+		processors.NamespacePreflight(ctx, t.cloudAccountPrismaClient, path, cluster.Name),
+	}
+
+	if cluster.Status != "RUNNING" {
+		return append(result, lib_types.NewPreflightCheck("", cluster.Name, "kubernetes").SetSkip("cluster is not active"))
+	}
+
+	kubernetesClientset, err := getKubernetesClientset(cluster)
+	if err != nil {
+		return append(result, lib_types.NewPreflightCheck("", cluster.Name, "kubernetes client").SetError(err))
+	}
+
+	// The processor is only used to evaluate the requirements so the cloud account client is
+	// sufficient
+	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, t.cloudAccountPrismaClient)
+
+	return append(result, kubeprocessor.
+		SetKubernetesDaemonsetBuilder(builder.NewGke(t.namespace+"/"+path, t.api)).
+		SetTags(cluster.ResourceLabels).
+		SetKubernetesClientset(kubernetesClientset).
+		Preflight(ctx)...)
+}
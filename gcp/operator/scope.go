@@ -0,0 +1,69 @@
+package operator
+
+import (
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// operatingSystems a policy is created for each host operating system
+var operatingSystems = []struct {
+	name string
+	tag  string
+}{
+	{"Linux", "@os:host=linux"},
+	{"Windows", "@os:host=windows"},
+}
+
+// policyScope is a set of hosts selected by a policy. The tags are added to the cloud
+// account and tenant of the subject.
+type policyScope struct {
+	name string
+	tags []string
+}
+
+// policyScopes returns the scopes of the configured DHCPMode. Each name starts with prefix.
+func (t *Client) policyScopes(prefix string) []*policyScope {
+
+	var result []*policyScope
+
+	switch t.cloudOperatorConfig.GetDHCPMode() {
+
+	case lib_types.DHCPModeNetwork:
+		for _, network := range t.Networks {
+			result = append(result, &policyScope{
+				name: prefix + " " + network.Name,
+				tags: []string{networkTag + network.Name},
+			})
+		}
+
+	case lib_types.DHCPModeSubnet:
+		for _, subnetwork := range t.Subnetworks {
+			scope := &policyScope{
+				name: prefix + " " + subnetwork.Name,
+				tags: []string{subnetworkTag + subnetwork.Name},
+			}
+			if subnetwork.VpcNetwork != nil {
+				scope.tags = append([]string{networkTag + subnetwork.VpcNetwork.Name}, scope.tags...)
+			}
+			result = append(result, scope)
+		}
+
+	default:
+		result = append(result, &policyScope{name: prefix})
+
+	}
+
+	return result
+}
+
+// subject returns the subject of the scope for the operating system tag
+func (t *Client) subject(scope *policyScope, operatingSystemTag string) []string {
+
+	subject := []string{
+		"@org:cloudaccount=" + t.orgCloudAccount,
+		"@org:tenant=" + t.orgTenant,
+	}
+
+	subject = append(subject, scope.tags...)
+
+	return append(subject, operatingSystemTag)
+}
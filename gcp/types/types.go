@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/aporeto-se/cloud-operator/common/types"
 )
 
@@ -20,6 +22,9 @@ type CloudOperatorConfig struct {
 
 	// Google Cloud Zone
 	GCloudZone string
+
+	// DHCPMode controls how the DHCP and infrastructure services policies are grouped
+	DHCPMode types.DHCPMode `json:"dhcpMode,omitempty" yaml:"dhcpMode,omitempty"`
 }
 
 // SetFromEnv sets attributes and types from env variables as defined in
@@ -40,7 +45,24 @@ func (t *CloudOperatorConfig) SetFromEnv() error {
 		t.GCloudZone = gCloudZone
 	}
 
-	return t.CloudOperatorConfig.SetFromEnv()
+	var errors *multierror.Error
+
+	dhcpModeString := os.Getenv(types.DHCPModeEnv)
+	if dhcpModeString != "" {
+		dhcpMode, err := types.DHCPModeFromString(dhcpModeString)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		} else {
+			t.DHCPMode = dhcpMode
+		}
+	}
+
+	err := t.CloudOperatorConfig.SetFromEnv()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	return errors.ErrorOrNil()
 }
 
 // SetGCloudProject sets attribute and returns self
@@ -72,3 +94,17 @@ func (t *CloudOperatorConfig) GetGCloudZone() (string, error) {
 	}
 	return t.GCloudZone, err
 }
+
+// SetDHCPMode sets type and returns self
+func (t *CloudOperatorConfig) SetDHCPMode(v types.DHCPMode) *CloudOperatorConfig {
+	t.DHCPMode = v
+	return t
+}
+
+// GetDHCPMode returns type. If type is not set DHCPModeAccount is returned.
+func (t *CloudOperatorConfig) GetDHCPMode() types.DHCPMode {
+	if t.DHCPMode == "" {
+		return types.DHCPModeAccount
+	}
+	return t.DHCPMode
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+	helper "github.com/aporeto-se/cloud-operator/kubeconfig/functions"
+)
+
+func main() {
+
+	ctx := context.Background()
+
+	err := run(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+}
+
+func run(ctx context.Context) error {
+
+	// The override applies to this invocation only
+	namespaceDeleteOverride := flag.Bool("namespace-delete-override", false, "disable the namespace delete maximum count and percentage checks for this run")
+	flag.Parse()
+
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+
+		case "preflight":
+			return preflight(ctx)
+
+		case "permissions":
+			return printPermissions()
+
+		default:
+			return fmt.Errorf("command %s is not valid. Valid commands are: preflight, permissions", flag.Arg(0))
+
+		}
+	}
+
+	operator, err := helper.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	report := operator.Run(ctx, lib_types.NewRunInput().SetNamespaceDeleteOverride(*namespaceDeleteOverride))
+	jsonReport, _ := json.Marshal(report)
+	fmt.Println(string(jsonReport))
+
+	return err
+}
+
+// preflight prints the permission checks for the enabled ops. An error is returned if any
+// check failed.
+func preflight(ctx context.Context) error {
+
+	report, err := helper.Preflight(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	jsonReport, _ := json.Marshal(report)
+	fmt.Println(string(jsonReport))
+
+	if report.Status == lib_types.PreflightStatusFail {
+		return fmt.Errorf("%d of %d preflight checks failed", report.FailCount, report.TotalCount)
+	}
+
+	return nil
+}
+
+// printPermissions prints the Prisma API authorization policy and Kubernetes ClusterRole
+// required by the enabled ops
+func printPermissions() error {
+
+	manifest, err := helper.Permissions()
+	if err != nil {
+		return err
+	}
+
+	jsonManifest, _ := json.MarshalIndent(manifest, "", "  ")
+	fmt.Println(string(jsonManifest))
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package helper
+
+import (
+	"context"
+	"net/http"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/common/permissions"
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/functions/types"
+	operator "github.com/aporeto-se/cloud-operator/kubeconfig/operator"
+)
+
+// tokenProvider returns a static Prisma API token
+type tokenProvider struct {
+	token string
+}
+
+// GetToken returns the token
+func (t *tokenProvider) GetToken(ctx context.Context) (string, error) {
+	return t.token, nil
+}
+
+// NewClient returns new Client
+func NewClient(ctx context.Context) (*operator.Client, error) {
+
+	config, err := NewConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Build(ctx)
+}
+
+// Preflight returns the permission checks for the enabled ops
+func Preflight(ctx context.Context, filter *lib_types.Filter) (*lib_types.PreflightReport, error) {
+
+	config, err := NewConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return operator.Preflight(ctx, config, filter), nil
+}
+
+// Permissions returns the permissions required by the enabled ops in the config file and env
+func Permissions() (*permissions.Manifest, error) {
+
+	cloudOperatorConfig := types.NewCloudOperatorConfig()
+	err := lib_types.LoadConfigFile(cloudOperatorConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cloudOperatorConfig.SetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return operator.Permissions(&cloudOperatorConfig.CloudOperatorConfig), nil
+}
+
+// NewConfig returns new operator Config from the config file and env
+func NewConfig(ctx context.Context) (*operator.Config, error) {
+
+	// Logging has NOT been initialized yet
+
+	cloudOperatorConfig := types.NewCloudOperatorConfig()
+	err := lib_types.LoadConfigFile(cloudOperatorConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cloudOperatorConfig.SetFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	err = operator.InitLogging(cloudOperatorConfig.GetLogLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	// Logging is now initialized
+
+	zap.L().Debug("logging initialized : inside NewOperator")
+
+	var errors *multierror.Error
+
+	api, err := cloudOperatorConfig.GetAPI()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	namespace, err := cloudOperatorConfig.GetNamespace()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	token, err := cloudOperatorConfig.GetToken()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = errors.ErrorOrNil()
+
+	if err != nil {
+		zap.L().Debug("returning NewOperator with error(s)")
+		return nil, err
+	}
+
+	httpClient := &http.Client{}
+
+	prismaClient, err := prisma_api.NewConfig().
+		SetNamespace(namespace).
+		SetAPI(api).
+		SetTokenProvider(&tokenProvider{token: token}).
+		SetHTTPClient(httpClient).Build(ctx)
+	if err != nil {
+		zap.L().Debug("returning NewOperator with error(s)")
+		return nil, err
+	}
+
+	zap.L().Debug("returning NewOperator")
+	return operator.NewConfig().
+		SetCloudOperatorConfig(&cloudOperatorConfig.CloudOperatorConfig).
+		SetPrismaClient(prismaClient).SetHTTPClient(httpClient), nil
+}
@@ -0,0 +1,81 @@
+package helper
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/functions/types"
+	kube_types "github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// writeConfigFile writes data to a config file and points the config file env var to it
+func writeConfigFile(t *testing.T, data string) {
+
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+
+	err := ioutil.WriteFile(file, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(lib_types.ConfigFileEnv, file)
+}
+
+func TestConfigFileContexts(t *testing.T) {
+
+	writeConfigFile(t, `
+kubeconfig:
+- /etc/kubeconfig
+contexts:
+  prod:
+    region: dc1
+    cidrBlocks:
+    - 10.0.0.0/16
+    enforcerProfile: GKE
+`)
+
+	cloudOperatorConfig := types.NewCloudOperatorConfig()
+
+	err := lib_types.LoadConfigFile(cloudOperatorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := kube_types.NewContextConfig().
+		SetRegion("dc1").
+		AddCidrBlocks("10.0.0.0/16").
+		SetEnforcerProfile(kube_types.EnforcerProfileGKE)
+
+	if got := cloudOperatorConfig.Contexts["prod"]; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestNewConfigRequiresToken(t *testing.T) {
+
+	writeConfigFile(t, "kubeconfig:\n- /etc/kubeconfig\n")
+	t.Setenv(lib_types.APIEnv, "https://api.example.com")
+	t.Setenv(lib_types.OrgTenantEnv, "tenant")
+	t.Setenv(lib_types.OrgCloudAccountEnv, "account")
+	t.Setenv(types.TokenEnv, "")
+
+	if _, err := NewConfig(context.Background()); err == nil {
+		t.Fatalf("expected error without token")
+	}
+}
+
+func TestTokenProvider(t *testing.T) {
+
+	provider := &tokenProvider{token: "token"}
+
+	got, err := provider.GetToken(context.Background())
+	if err != nil || got != "token" {
+		t.Fatalf("got %s %v, want token", got, err)
+	}
+}
@@ -0,0 +1,11 @@
+package types
+
+import (
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const (
+
+	// TokenEnv enviroment variable
+	TokenEnv = lib_types.PrismaPrependEnv + "TOKEN"
+)
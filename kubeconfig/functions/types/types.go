@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// CloudOperatorConfig Kubeconfig Implementation
+type CloudOperatorConfig struct {
+	types.CloudOperatorConfig
+
+	// Token is the Prisma API token. Self managed clusters have no cloud identity to
+	// exchange for a token.
+	Token string `json:"-" yaml:"-"`
+}
+
+// NewCloudOperatorConfig returns new instance of CloudOperatorConfig
+func NewCloudOperatorConfig() *CloudOperatorConfig {
+	return &CloudOperatorConfig{}
+}
+
+// SetFromEnv sets attributes and types from env variables as defined in
+// constants file. If attribute is not of the expected type an error will be
+// returned. If child entities exist and are initialized (not nil) then a call
+// to the child entities SetFromEnv() will be executed. Any errors will be aggregated
+// and returned.
+func (t *CloudOperatorConfig) SetFromEnv() error {
+
+	token := os.Getenv(TokenEnv)
+
+	if token != "" {
+		t.Token = token
+	}
+
+	return t.CloudOperatorConfig.SetFromEnv()
+}
+
+// GetToken returns attribute or error
+func (t *CloudOperatorConfig) GetToken() (string, error) {
+	var err error
+	if t.Token == "" {
+		err = fmt.Errorf("attribute Token (env var %s) is required", TokenEnv)
+	}
+	return t.Token, err
+}
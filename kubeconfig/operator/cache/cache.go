@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// Cache server
+type Cache struct {
+	kubeconfig []string
+	contexts   map[string]*types.ContextConfig
+	Clusters   []*Cluster
+}
+
+func (t *Cache) init(ctx context.Context) error {
+
+	zap.L().Debug("entering init")
+
+	var files []string
+
+	for _, path := range t.kubeconfig {
+
+		info, err := os.Stat(path)
+		if err != nil {
+			zap.L().Debug("returning init with error(s)")
+			return err
+		}
+
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			zap.L().Debug("returning init with error(s)")
+			return err
+		}
+
+		// ReadDir returns the entries sorted by name. Hidden files (such as editor swap
+		// files or a .git directory) are skipped.
+		for _, entry := range entries {
+			if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+
+	seen := make(map[string]string)
+
+	for _, file := range files {
+
+		config, err := clientcmd.LoadFromFile(file)
+		if err != nil {
+			zap.L().Debug("returning init with error(s)")
+			return fmt.Errorf("unable to load kubeconfig %s: %w", file, err)
+		}
+
+		var names []string
+		for name := range config.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+
+			// The context name is the cluster name so it must be unique across all files
+			if source, ok := seen[name]; ok {
+				zap.L().Warn(fmt.Sprintf("context %s in %s is already defined in %s; skipping", name, file, source))
+				continue
+			}
+
+			restConfig, err := clientcmd.NewNonInteractiveClientConfig(*config, name, &clientcmd.ConfigOverrides{CurrentContext: name}, nil).ClientConfig()
+			if err != nil {
+				zap.L().Debug("returning init with error(s)")
+				return fmt.Errorf("unable to load context %s from kubeconfig %s: %w", name, file, err)
+			}
+
+			contextConfig, ok := t.contexts[name]
+			if !ok || contextConfig == nil {
+				contextConfig = types.NewContextConfig()
+			}
+
+			seen[name] = file
+			t.Clusters = append(t.Clusters, newCluster(name, file, contextConfig, restConfig))
+		}
+	}
+
+	zap.L().Debug(fmt.Sprintf("returning init; %d contexts found", len(t.Clusters)))
+	return nil
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// writeKubeconfig writes a kubeconfig with a context, cluster and user per name to file
+func writeKubeconfig(t *testing.T, file string, names ...string) {
+
+	t.Helper()
+
+	data := "apiVersion: v1\nkind: Config\nclusters:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  cluster:\n    server: https://%s.example.com\n", name, name)
+	}
+
+	data += "users:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  user:\n    token: token\n", name)
+	}
+
+	data += "contexts:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  context:\n    cluster: %s\n    user: %s\n", name, name, name)
+	}
+
+	err := ioutil.WriteFile(file, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// clusterNames returns the name and source of each cluster
+func clusterNames(clusters []*Cluster) []string {
+	var result []string
+	for _, cluster := range clusters {
+		result = append(result, cluster.Name+"@"+filepath.Base(cluster.Source))
+	}
+	return result
+}
+
+func TestBuild(t *testing.T) {
+
+	dir := t.TempDir()
+
+	writeKubeconfig(t, filepath.Join(dir, "b.yaml"), "prod", "dev")
+	writeKubeconfig(t, filepath.Join(dir, "a.yaml"), "test")
+
+	// Skipped: hidden, in a sub directory or a duplicate context
+	writeKubeconfig(t, filepath.Join(dir, ".a.yaml.swp"), "swap")
+	err := os.Mkdir(filepath.Join(dir, "sub"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKubeconfig(t, filepath.Join(dir, "sub", "config"), "nested")
+	writeKubeconfig(t, filepath.Join(dir, "c.yaml"), "prod")
+
+	single := filepath.Join(t.TempDir(), "config")
+	writeKubeconfig(t, single, "single")
+
+	cache, err := NewConfig().AddKubeconfig(dir, single).Build(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"test@a.yaml", "dev@b.yaml", "prod@b.yaml", "single@config"}
+
+	if got := clusterNames(cache.Clusters); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	if got := cache.Clusters[0].Endpoint; got != "https://test.example.com" {
+		t.Fatalf("got endpoint %s, want https://test.example.com", got)
+	}
+}
+
+func TestBuildContextConfig(t *testing.T) {
+
+	file := filepath.Join(t.TempDir(), "config")
+	writeKubeconfig(t, file, "dev", "prod")
+
+	prod := types.NewContextConfig().
+		SetRegion("dc1").
+		SetEnforcerProfile(types.EnforcerProfileGKE).
+		AddCidrBlocks("10.0.0.0/16")
+
+	cache, err := NewConfig().
+		AddKubeconfig(file).
+		SetContexts(map[string]*types.ContextConfig{"prod": prod}).
+		Build(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cache.Clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2", len(cache.Clusters))
+	}
+
+	// Contexts without a config get an empty one
+	if dev := cache.Clusters[0]; dev.ContextConfig == nil || dev.EnforcerProfile != "" {
+		t.Fatalf("got context config %v, want an empty one", dev.ContextConfig)
+	}
+
+	if got := cache.Clusters[1].ContextConfig; got != prod {
+		t.Fatalf("got context config %v, want %v", got, prod)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+
+	dir := t.TempDir()
+
+	invalid := filepath.Join(dir, "invalid")
+	err := ioutil.WriteFile(invalid, []byte("contexts: ["), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		kubeconfig []string
+	}{
+		"none":      {},
+		"missing":   {kubeconfig: []string{filepath.Join(dir, "missing")}},
+		"not valid": {kubeconfig: []string{invalid}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewConfig().AddKubeconfig(test.kubeconfig...).Build(context.Background()); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package cache
+
+import (
+	"k8s.io/client-go/rest"
+
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// Cluster is a kubeconfig context
+type Cluster struct {
+	*types.ContextConfig
+	Name       string
+	Source     string
+	Endpoint   string
+	RestConfig *rest.Config
+}
+
+func newCluster(name, source string, contextConfig *types.ContextConfig, restConfig *rest.Config) *Cluster {
+	return &Cluster{
+		ContextConfig: contextConfig,
+		Name:          name,
+		Source:        source,
+		Endpoint:      restConfig.Host,
+		RestConfig:    restConfig,
+	}
+}
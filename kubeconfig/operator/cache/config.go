@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// Config ...
+type Config struct {
+	Kubeconfig []string
+	Contexts   map[string]*types.ContextConfig
+}
+
+// NewConfig returns a new entity instance
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// AddKubeconfig adds attribute(s) and returns self
+func (t *Config) AddKubeconfig(kubeconfig ...string) *Config {
+	t.Kubeconfig = append(t.Kubeconfig, kubeconfig...)
+	return t
+}
+
+// SetContexts sets entity and returns self
+func (t *Config) SetContexts(contexts map[string]*types.ContextConfig) *Config {
+	t.Contexts = contexts
+	return t
+}
+
+// Build returns new Cache from config or error
+func (t *Config) Build(ctx context.Context) (*Cache, error) {
+
+	zap.L().Debug("entering Build")
+
+	if len(t.Kubeconfig) == 0 {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, fmt.Errorf("kubeconfig is required")
+	}
+
+	c := &Cache{
+		kubeconfig: t.Kubeconfig,
+		contexts:   t.Contexts,
+	}
+
+	err := c.init(ctx)
+	if err != nil {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, err
+	}
+
+	zap.L().Debug("returning Build")
+	return c, nil
+}
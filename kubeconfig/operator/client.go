@@ -0,0 +1,458 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	builder "github.com/aporeto-se/enforcerd-kube-builder"
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+	"github.com/hashicorp/go-multierror"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/aporeto-se/cloud-operator/common/layout"
+	"github.com/aporeto-se/cloud-operator/common/processors"
+	"github.com/aporeto-se/cloud-operator/common/reportwrapper"
+	"github.com/aporeto-se/cloud-operator/common/tag"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/operator/cache"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// Client the Kubeconfig Client implementation. Each kubeconfig context is processed as a
+// cluster. There are no compute instances and no cloud identities so only the Kubernetes
+// namespace and Kubernetes operations are supported.
+type Client struct {
+	*cache.Cache
+	cloudAccountPrismaClient *prisma_api.Client
+	cloudOperatorConfig      *types.CloudOperatorConfig
+	api                      string
+	orgTenant                string
+	orgCloudAccount          string
+	namespace                string
+	layout                   layout.NamespaceLayout
+}
+
+// NewClient returns new Client or error
+func NewClient(ctx context.Context, config *Config) (*Client, error) {
+
+	var errors *multierror.Error
+
+	if config.CloudOperatorConfig == nil {
+		errors = multierror.Append(errors, fmt.Errorf("entity CloudOperatorConfig is required"))
+	}
+
+	if config.PrismaClient == nil {
+		errors = multierror.Append(errors, fmt.Errorf("entity PrismaClient is required"))
+	}
+
+	err := errors.ErrorOrNil()
+	if err != nil {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, err
+	}
+
+	kubeconfig, err := config.CloudOperatorConfig.GetKubeconfig()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	api, err := config.CloudOperatorConfig.GetAPI()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	orgTenant, err := config.CloudOperatorConfig.GetOrgTenant()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	orgCloudAccount, err := config.CloudOperatorConfig.GetOrgCloudAccount()
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	namespaceLayout, err := layout.NewNamespaceLayout(&config.CloudOperatorConfig.CloudOperatorConfig)
+	if err != nil {
+		errors = multierror.Append(errors, err)
+	}
+
+	err = errors.ErrorOrNil()
+	if err != nil {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, err
+	}
+
+	cache, err := cache.NewConfig().
+		AddKubeconfig(kubeconfig...).
+		SetContexts(config.CloudOperatorConfig.Contexts).
+		Build(ctx)
+	if err != nil {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, err
+	}
+
+	err = validateEnforcerProfiles(config.CloudOperatorConfig, cache.Clusters)
+	if err != nil {
+		zap.L().Debug("returning Build with error(s)")
+		return nil, err
+	}
+
+	return &Client{
+		cloudAccountPrismaClient: config.PrismaClient,
+		cloudOperatorConfig:      config.CloudOperatorConfig,
+		api:                      api,
+		orgTenant:                orgTenant,
+		orgCloudAccount:          orgCloudAccount,
+		namespace:                "/" + orgTenant + "/" + orgCloudAccount,
+		layout:                   namespaceLayout,
+		Cache:                    cache,
+	}, nil
+
+}
+
+// kubeEntity returns the namespace entity for cluster
+func (t *Client) kubeEntity(cluster *cache.Cluster) *lib_types.NamespaceEntity {
+
+	entity := lib_types.NewNamespaceEntity(lib_types.CloudEntityTypeKubernetes, cluster.Name).
+		SetRegion(cluster.Region).
+		SetNetwork(cluster.Network)
+
+	for key, value := range cluster.Labels {
+		entity.AddTag(key, value)
+	}
+
+	return entity
+}
+
+// conflicts returns an error keyed by the namespace path of each cluster that collides with
+// another cluster. Nothing is written to these paths.
+func (t *Client) conflicts() map[string]error {
+
+	var entities []*lib_types.NamespaceEntity
+
+	for _, cluster := range t.Clusters {
+		entities = append(entities, t.kubeEntity(cluster))
+	}
+
+	return layout.Conflicts(t.layout, entities...)
+}
+
+// Run run and returns report. Any error(s) will be wrapped in report. The input only applies
+// to this run and may be nil.
+func (t *Client) Run(ctx context.Context, input *lib_types.RunInput) *lib_types.Report {
+
+	zap.L().Debug("entering Run")
+
+	if input == nil {
+		input = lib_types.NewRunInput()
+	}
+
+	tagMatcher, _ := tag.NewMatcher(&t.cloudOperatorConfig.Filter, input.Filter)
+
+	// Clusters whose namespaces collide are skipped by every op that writes to the namespace
+	conflicts := t.conflicts()
+
+	report := lib_types.NewReport(cloudProvider)
+
+	for _, op := range []lib_types.Op{
+		lib_types.OpDHCP,
+		lib_types.OpInfraServices,
+		lib_types.OpNamespaceComputeCreate,
+		lib_types.OpNamespaceComputeDelete,
+		lib_types.OpComputeAuth,
+		lib_types.OpKubeAuth,
+		lib_types.OpKubeWorkloadAuth,
+	} {
+		if t.cloudOperatorConfig.HasOp(op) {
+			zap.L().Warn(fmt.Sprintf("%s operation is not supported for kubeconfig clusters", op))
+		}
+	}
+
+	if t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceRogueDelete) ||
+		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceKubeCreate) ||
+		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceKubeDelete) ||
+		t.cloudOperatorConfig.HasOp(lib_types.OpNamespaceMigrateNames) {
+
+		zap.L().Debug("Namespace operation is enabled")
+
+		nsprocessor, err := processors.NewNamespaceProcessor(&t.cloudOperatorConfig.CloudOperatorConfig, t.cloudAccountPrismaClient)
+		if err != nil {
+			report.SetNamespace(lib_types.NewNamespaceReports().SetError(err))
+		} else {
+			nsprocessor.SetDeleteOverride(input.NamespaceDeleteOverride)
+
+			for _, cluster := range t.Clusters {
+				nsprocessor.AddKube(t.kubeEntity(cluster))
+				zap.L().Debug(fmt.Sprintf("kubernetes namespace %s added to add list", cluster.Name))
+			}
+
+			report.SetNamespace(nsprocessor.Process(ctx))
+		}
+
+	} else {
+		zap.L().Debug("Namespace operation is disabled")
+	}
+
+	runKube := false
+	for _, op := range t.cloudOperatorConfig.Ops {
+
+		switch op {
+
+		case lib_types.OpKubeAPINet:
+			runKube = true
+
+		case lib_types.OpKubeDNSNet:
+			runKube = true
+
+		case lib_types.OpKubeNodesNet:
+			runKube = true
+
+		case lib_types.OpKubeClusterNets:
+			runKube = true
+
+		case lib_types.OpKubeEnforcer:
+			runKube = true
+
+		case lib_types.OpKubeEnforcerUninstall:
+			runKube = true
+
+		}
+
+	}
+
+	if runKube {
+		report.SetKubernetes(t.kubernetesReports(ctx, tagMatcher, conflicts))
+	} else {
+		zap.L().Debug("Kubernetes operations are disabled")
+	}
+
+	zap.L().Debug("returning Run")
+	return report.Build()
+}
+
+func (t *Client) kubernetesReports(ctx context.Context, tagMatcher *tag.Matcher, conflicts map[string]error) *lib_types.KubernetesReports {
+
+	zap.L().Debug("entering kubernetesReports")
+
+	wrapper := reportwrapper.NewWrapper().
+		SetConcurrency(t.cloudOperatorConfig.GetKubeConcurrency()).
+		SetTimeout(t.cloudOperatorConfig.GetKubeClusterTimeout())
+
+	for _, _cluster := range t.Clusters {
+
+		cluster := _cluster
+
+		if err, ok := conflicts[t.layout.Path(t.kubeEntity(cluster))]; ok {
+			zap.L().Debug(fmt.Sprintf("Cluster %s namespace conflict", cluster.Name))
+			wrapper.AddKube(lib_types.NewKubernetesReport(cluster.Name).
+				SetStatus(lib_types.OpStatusFailed).
+				SetError(err))
+			continue
+		}
+
+		if tagMatcher.MatchKubeCluster(cluster.Name, cluster.Labels) {
+			zap.L().Debug(fmt.Sprintf("Cluster %s is a match", cluster.Name))
+
+			wrapper.RunKube(ctx, cluster.Name, func(ctx context.Context) *lib_types.KubernetesReport {
+				return t.kubernetesReport(ctx, cluster)
+			})
+
+		} else if t.cloudOperatorConfig.HasOp(lib_types.OpKubeEnforcerUninstall) && !tagMatcher.InScopeKubeCluster(cluster.Name, cluster.Labels) {
+			zap.L().Debug(fmt.Sprintf("Cluster %s is out of scope; uninstalling", cluster.Name))
+
+			wrapper.RunKube(ctx, cluster.Name, func(ctx context.Context) *lib_types.KubernetesReport {
+				return t.kubernetesUninstallReport(ctx, cluster)
+			})
+
+		} else {
+			zap.L().Debug(fmt.Sprintf("Cluster %s is NOT a match", cluster.Name))
+		}
+
+	}
+
+	zap.L().Debug("returning kubernetesReports")
+	return wrapper.Build()
+}
+
+func (t *Client) kubernetesReport(ctx context.Context, cluster *cache.Cluster) *lib_types.KubernetesReport {
+
+	zap.L().Debug("entering kubernetesReport")
+
+	report := lib_types.NewKubernetesReport(cluster.Name).
+		SetOperation(lib_types.KubernetesOperationSync).
+		SetStatus(lib_types.OpStatusFailed)
+
+	if cluster.Endpoint == "" {
+		zap.L().Debug("returning kubernetesReport with error(s)")
+		return report.SetError(fmt.Errorf("unable to determine cluster endpoint"))
+	}
+
+	zap.L().Debug(fmt.Sprintf("endpoint=%s", cluster.Endpoint))
+
+	kubernetesClientset, err := kubernetes.NewForConfig(cluster.RestConfig)
+	if err != nil {
+		zap.L().Debug("returning kubernetesReport with error(s)")
+		return report.SetError(err)
+	}
+
+	prismaClient, err := layout.NewClient(ctx, t.cloudAccountPrismaClient, t.layout.Path(t.kubeEntity(cluster)))
+	if err != nil {
+		zap.L().Debug("returning kubernetesReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	daemonsetBuilder, skipEnforcer := enforcerBuilder(cluster, prismaClient.GetNamespacePath(), t.api)
+
+	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, prismaClient)
+	err = kubeprocessor.
+		AddCidrBlocks(cluster.CidrBlocks...).
+		AddNodeCidrBlocks(cluster.NodeCidrBlocks...).
+		AddPodCidrBlocks(cluster.PodCidrBlocks...).
+		AddServiceCidrBlocks(cluster.ServiceCidrBlocks...).
+		SetKubernetesDaemonsetBuilder(daemonsetBuilder).
+		SetSkipEnforcer(skipEnforcer).
+		SetEndpoint(cluster.Endpoint).
+		SetTags(cluster.Labels).
+		SetKubernetesClientset(kubernetesClientset).
+		Process(ctx)
+
+	report.SetRollout(kubeprocessor.Rollout()).AddDrift(kubeprocessor.Drift()...).SetExport(kubeprocessor.Export())
+
+	if err != nil {
+		zap.L().Debug("returning kubernetesReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning kubernetesReport")
+
+	return report.SetStatus(lib_types.OpStatusCompleted)
+
+}
+
+// kubernetesUninstallReport removes the enforcer and Prisma config from a cluster that is
+// no longer in scope
+func (t *Client) kubernetesUninstallReport(ctx context.Context, cluster *cache.Cluster) *lib_types.KubernetesReport {
+
+	zap.L().Debug("entering kubernetesUninstallReport")
+
+	report := lib_types.NewKubernetesReport(cluster.Name).
+		SetOperation(lib_types.KubernetesOperationUninstall).
+		SetStatus(lib_types.OpStatusFailed)
+
+	kubernetesClientset, err := kubernetes.NewForConfig(cluster.RestConfig)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with error(s)")
+		return report.SetError(err)
+	}
+
+	path := t.layout.Path(t.kubeEntity(cluster))
+
+	// Without the cluster namespace nothing was ever installed by the operator
+	exists, err := layout.Exists(ctx, t.cloudAccountPrismaClient, path)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	if !exists {
+		zap.L().Debug("returning kubernetesUninstallReport (no namespace)")
+		return report.SetStatus(lib_types.OpStatusNothingToDo)
+	}
+
+	prismaClient, err := layout.NewClient(ctx, t.cloudAccountPrismaClient, path)
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	// The managed objects are only known from the profile the enforcer was installed with
+	daemonsetBuilder, skipEnforcer := enforcerBuilder(cluster, prismaClient.GetNamespacePath(), t.api)
+	if daemonsetBuilder == nil {
+		zap.L().Debug("returning kubernetesUninstallReport with error(s)")
+		return report.SetError(fmt.Errorf("unable to uninstall: %s", skipEnforcer))
+	}
+
+	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, prismaClient)
+
+	removed, err := kubeprocessor.
+		SetKubernetesDaemonsetBuilder(daemonsetBuilder).
+		SetKubernetesClientset(kubernetesClientset).
+		SetTags(cluster.Labels).
+		Uninstall(ctx)
+
+	if err != nil {
+		zap.L().Debug("returning kubernetesUninstallReport with wrapped error(s)")
+		return report.SetError(err)
+	}
+
+	zap.L().Debug("returning kubernetesUninstallReport")
+
+	if !removed {
+		return report.SetStatus(lib_types.OpStatusNothingToDo)
+	}
+
+	return report.SetStatus(lib_types.OpStatusCompleted)
+}
+
+// validateEnforcerProfiles returns an error for each cluster whose enforcer profile is invalid.
+// The enforcer objects are only known from the profile so it is required if the enforcer is
+// installed or uninstalled.
+func validateEnforcerProfiles(config *types.CloudOperatorConfig, clusters []*cache.Cluster) error {
+
+	var errors *multierror.Error
+
+	required := config.HasOp(lib_types.OpKubeEnforcer) || config.HasOp(lib_types.OpKubeEnforcerUninstall)
+
+	for _, cluster := range clusters {
+
+		if cluster.EnforcerProfile == "" && !required {
+			continue
+		}
+
+		_, err := enforcerProfile(cluster)
+		if err != nil {
+			errors = multierror.Append(errors, err)
+		}
+	}
+
+	return errors.ErrorOrNil()
+}
+
+// enforcerProfile returns the enforcer profile of cluster or an error if it is not set or invalid
+func enforcerProfile(cluster *cache.Cluster) (types.EnforcerProfile, error) {
+
+	if cluster.EnforcerProfile == "" {
+		return types.EnforcerProfileInvalid, fmt.Errorf("context %s has no enforcer profile (EKS or GKE)", cluster.Name)
+	}
+
+	profile, err := types.EnforcerProfileFromString(string(cluster.EnforcerProfile))
+	if err != nil {
+		return types.EnforcerProfileInvalid, fmt.Errorf("context %s enforcer profile is invalid: %w", cluster.Name, err)
+	}
+
+	return profile, nil
+}
+
+// enforcerBuilder returns the enforcer DaemonSet builder of the cluster enforcer profile. If
+// the profile is not set or invalid nil and the reason the enforcer is skipped are returned.
+func enforcerBuilder(cluster *cache.Cluster, namespace, api string) (*builder.Builder, string) {
+
+	profile, err := enforcerProfile(cluster)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	switch profile {
+
+	case types.EnforcerProfileEKS:
+		return builder.NewEks(namespace, api), ""
+
+	case types.EnforcerProfileGKE:
+		return builder.NewGke(namespace, api), ""
+
+	}
+
+	return nil, fmt.Sprintf("context %s enforcer profile %s is not supported", cluster.Name, profile)
+}
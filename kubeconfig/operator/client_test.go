@@ -0,0 +1,125 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	prisma_api "github.com/aporeto-se/prisma-sdk-go-v2/api"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/operator/cache"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/types"
+)
+
+// profileCluster returns a cluster with the enforcer profile
+func profileCluster(name string, profile types.EnforcerProfile) *cache.Cluster {
+	return &cache.Cluster{
+		Name:          name,
+		ContextConfig: types.NewContextConfig().SetEnforcerProfile(profile),
+	}
+}
+
+func TestEnforcerBuilder(t *testing.T) {
+
+	tests := map[string]struct {
+		profile     types.EnforcerProfile
+		wantBuilder bool
+	}{
+		"eks":     {profile: types.EnforcerProfileEKS, wantBuilder: true},
+		"gke":     {profile: types.EnforcerProfileGKE, wantBuilder: true},
+		"not set": {},
+		"invalid": {profile: "AKS"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			got, reason := enforcerBuilder(profileCluster("cluster1", test.profile), "/tenant/account/cluster1", "https://api.example.com")
+
+			if (got != nil) != test.wantBuilder {
+				t.Fatalf("got builder %v, want builder %t", got, test.wantBuilder)
+			}
+
+			if (reason == "") != test.wantBuilder {
+				t.Fatalf("got skip reason %q, want skip reason %t", reason, !test.wantBuilder)
+			}
+		})
+	}
+}
+
+func TestValidateEnforcerProfiles(t *testing.T) {
+
+	tests := map[string]struct {
+		ops      []lib_types.Op
+		profiles []types.EnforcerProfile
+		wantErr  bool
+	}{
+		"enforcer with profiles": {
+			ops:      []lib_types.Op{lib_types.OpKubeEnforcer},
+			profiles: []types.EnforcerProfile{types.EnforcerProfileEKS, types.EnforcerProfileGKE},
+		},
+		"enforcer without profile": {
+			ops:      []lib_types.Op{lib_types.OpKubeEnforcer},
+			profiles: []types.EnforcerProfile{types.EnforcerProfileEKS, ""},
+			wantErr:  true,
+		},
+		"uninstall without profile": {
+			ops:      []lib_types.Op{lib_types.OpKubeEnforcerUninstall},
+			profiles: []types.EnforcerProfile{""},
+			wantErr:  true,
+		},
+		"network ops without profile": {
+			ops:      []lib_types.Op{lib_types.OpKubeAPINet},
+			profiles: []types.EnforcerProfile{""},
+		},
+		"network ops with invalid profile": {
+			ops:      []lib_types.Op{lib_types.OpKubeAPINet},
+			profiles: []types.EnforcerProfile{"AKS"},
+			wantErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			config := types.NewCloudOperatorConfig()
+			config.AddOps(test.ops...)
+
+			var clusters []*cache.Cluster
+			for _, profile := range test.profiles {
+				clusters = append(clusters, profileCluster("cluster-"+string(profile), profile))
+			}
+
+			err := validateEnforcerProfiles(config, clusters)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientRequiresEnforcerProfile(t *testing.T) {
+
+	file := t.TempDir() + "/config"
+	writeKubeconfig(t, file, "cluster1")
+
+	cloudOperatorConfig := types.NewCloudOperatorConfig().AddKubeconfig(file)
+	cloudOperatorConfig.SetAPI("https://api.example.com").
+		SetOrgTenant("tenant").
+		SetOrgCloudAccount("account").
+		AddOps(lib_types.OpKubeEnforcer)
+
+	config := NewConfig().
+		SetCloudOperatorConfig(cloudOperatorConfig).
+		SetPrismaClient(&prisma_api.Client{})
+
+	if _, err := NewClient(context.Background(), config); err == nil {
+		t.Fatalf("expected error for a context without enforcer profile")
+	}
+
+	cloudOperatorConfig.SetContext("cluster1", types.NewContextConfig().SetEnforcerProfile(types.EnforcerProfileEKS))
+
+	if _, err := NewClient(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+}
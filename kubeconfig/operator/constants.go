@@ -0,0 +1,5 @@
+package operator
+
+const (
+	cloudProvider = "Kubernetes"
+)
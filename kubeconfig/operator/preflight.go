@@ -0,0 +1,88 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/aporeto-se/cloud-operator/common/processors"
+	"github.com/aporeto-se/cloud-operator/common/tag"
+	"github.com/aporeto-se/cloud-operator/kubeconfig/operator/cache"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+const prismaPreflightTarget = "prisma"
+
+// Preflight verifies the Prisma and Kubernetes permissions required by the enabled ops and
+// returns a check per op and target. Nothing is changed.
+func Preflight(ctx context.Context, config *Config, filter *lib_types.Filter) *lib_types.PreflightReport {
+
+	zap.L().Debug("entering Preflight")
+
+	report := lib_types.NewPreflightReport(cloudProvider)
+
+	if config.CloudOperatorConfig == nil || config.PrismaClient == nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "configuration").
+			SetError(fmt.Errorf("entities CloudOperatorConfig and PrismaClient are required"))).Build()
+	}
+
+	_, err := config.PrismaClient.AccountID(ctx)
+	report.AddChecks(lib_types.NewPreflightCheck("", prismaPreflightTarget, "read cloud account").SetError(err))
+
+	if err != nil {
+		zap.L().Debug("returning Preflight; cluster checks skipped")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "clusters").
+			SetSkip("cluster checks require the Prisma checks to pass")).Build()
+	}
+
+	client, err := NewClient(ctx, config)
+	if err != nil {
+		zap.L().Debug("returning Preflight with error(s)")
+		return report.AddChecks(lib_types.NewPreflightCheck("", cloudProvider, "load kubeconfig").SetError(err)).Build()
+	}
+
+	tagMatcher, _ := tag.NewMatcher(&client.cloudOperatorConfig.Filter, filter)
+
+	for _, cluster := range client.Clusters {
+
+		if !tagMatcher.MatchKubeCluster(cluster.Name, cluster.Labels) && !client.cloudOperatorConfig.HasOp(lib_types.OpKubeEnforcerUninstall) {
+			continue
+		}
+
+		report.AddChecks(client.preflightCluster(ctx, cluster)...)
+	}
+
+	zap.L().Debug("returning Preflight")
+	return report.Build()
+}
+
+func (t *Client) preflightCluster(ctx context.Context, cluster *cache.Cluster) []*lib_types.PreflightCheck {
+
+	path := t.layout.Path(t.kubeEntity(cluster))
+
+	result := []*lib_types.PreflightCheck{
+		processors.NamespacePreflight(ctx, t.cloudAccountPrismaClient, path, cluster.Name),
+	}
+
+	kubernetesClientset, err := kubernetes.NewForConfig(cluster.RestConfig)
+	if err != nil {
+		return append(result, lib_types.NewPreflightCheck("", cluster.Name, "kubernetes client").SetError(err))
+	}
+
+	// The processor is only used to evaluate the requirements so the cloud account client is
+	// sufficient
+	kubeprocessor, _ := processors.NewKubeProcessor(cluster.Name, &t.cloudOperatorConfig.CloudOperatorConfig, t.cloudAccountPrismaClient)
+
+	daemonsetBuilder, skipEnforcer := enforcerBuilder(cluster, t.namespace+"/"+path, t.api)
+
+	return append(result, kubeprocessor.
+		SetKubernetesDaemonsetBuilder(daemonsetBuilder).
+		SetSkipEnforcer(skipEnforcer).
+		SetTags(cluster.Labels).
+		SetKubernetesClientset(kubernetesClientset).
+		Preflight(ctx)...)
+}
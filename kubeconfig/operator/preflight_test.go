@@ -0,0 +1,45 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	lib_types "github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// writeKubeconfig writes a kubeconfig with a context, cluster and user per name to file
+func writeKubeconfig(t *testing.T, file string, names ...string) {
+
+	t.Helper()
+
+	data := "apiVersion: v1\nkind: Config\nclusters:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  cluster:\n    server: https://%s.example.com\n", name, name)
+	}
+
+	data += "users:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  user:\n    token: token\n", name)
+	}
+
+	data += "contexts:\n"
+	for _, name := range names {
+		data += fmt.Sprintf("- name: %s\n  context:\n    cluster: %s\n    user: %s\n", name, name, name)
+	}
+
+	err := ioutil.WriteFile(file, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPreflightRequiresConfig(t *testing.T) {
+
+	report := Preflight(context.Background(), NewConfig(), nil)
+
+	if len(report.Checks) != 1 || report.Checks[0].Status != lib_types.PreflightStatusFail {
+		t.Fatalf("expected a single failed configuration check, got %v", report.Checks)
+	}
+}
@@ -0,0 +1,7 @@
+package types
+
+const (
+	// KubeconfigEnv enviroment variable. Kubeconfig files or directories of kubeconfig files
+	// separated by the OS path list separator.
+	KubeconfigEnv = "KUBECONFIG"
+)
@@ -0,0 +1,40 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ================================================================================================
+
+// EnforcerProfile EnforcerProfile
+type EnforcerProfile string
+
+const (
+	// EnforcerProfileInvalid invalid
+	EnforcerProfileInvalid EnforcerProfile = "INVALID"
+
+	// EnforcerProfileEKS the enforcer DaemonSet built for EKS
+	EnforcerProfileEKS EnforcerProfile = "EKS"
+
+	// EnforcerProfileGKE the enforcer DaemonSet built for GKE
+	EnforcerProfileGKE EnforcerProfile = "GKE"
+)
+
+// EnforcerProfileFromString returns type from string or error
+func EnforcerProfileFromString(s string) (EnforcerProfile, error) {
+
+	switch strings.ToUpper(s) {
+
+	case string(EnforcerProfileEKS):
+		return EnforcerProfileEKS, nil
+
+	case string(EnforcerProfileGKE):
+		return EnforcerProfileGKE, nil
+
+	}
+
+	return EnforcerProfileInvalid, fmt.Errorf("string %s is not a valid type", s)
+}
+
+// ================================================================================================
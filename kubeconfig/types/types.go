@@ -0,0 +1,147 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aporeto-se/cloud-operator/common/types"
+)
+
+// ================================================================================================
+
+// CloudOperatorConfig Kubeconfig Config
+type CloudOperatorConfig struct {
+
+	// Parent
+	types.CloudOperatorConfig
+
+	// Kubeconfig is the list of kubeconfig files or directories of kubeconfig files. Each
+	// context is processed as a cluster.
+	Kubeconfig []string `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+
+	// Contexts are the attributes of the clusters that can not be discovered from the
+	// kubeconfig, keyed by context name
+	Contexts map[string]*ContextConfig `json:"contexts,omitempty" yaml:"contexts,omitempty"`
+}
+
+// NewCloudOperatorConfig returns new instance of CloudOperatorConfig
+func NewCloudOperatorConfig() *CloudOperatorConfig {
+	return &CloudOperatorConfig{}
+}
+
+// SetFromEnv sets attributes and types from env variables as defined in
+// constants file. If attribute is not of the expected type an error will be
+// returned. If child entities exist and are initialized (not nil) then a call
+// to the child entities SetFromEnv() will be executed. Any errors will be aggregated
+// and returned.
+func (t *CloudOperatorConfig) SetFromEnv() error {
+
+	kubeconfig := os.Getenv(KubeconfigEnv)
+
+	if kubeconfig != "" {
+		t.Kubeconfig = nil
+		for _, path := range filepath.SplitList(kubeconfig) {
+			if path != "" {
+				t.AddKubeconfig(path)
+			}
+		}
+	}
+
+	return t.CloudOperatorConfig.SetFromEnv()
+}
+
+// AddKubeconfig adds attribute(s) and returns self
+func (t *CloudOperatorConfig) AddKubeconfig(v ...string) *CloudOperatorConfig {
+	t.Kubeconfig = append(t.Kubeconfig, v...)
+	return t
+}
+
+// GetKubeconfig returns attribute or error
+func (t *CloudOperatorConfig) GetKubeconfig() ([]string, error) {
+	var err error
+	if len(t.Kubeconfig) == 0 {
+		err = fmt.Errorf("attribute Kubeconfig (env var %s) is required", KubeconfigEnv)
+	}
+	return t.Kubeconfig, err
+}
+
+// SetContext sets entity for context name and returns self
+func (t *CloudOperatorConfig) SetContext(name string, v *ContextConfig) *CloudOperatorConfig {
+	if t.Contexts == nil {
+		t.Contexts = make(map[string]*ContextConfig)
+	}
+	t.Contexts[name] = v
+	return t
+}
+
+// ================================================================================================
+
+// ContextConfig holds the attributes of a cluster that are provided by the cloud APIs for
+// managed clusters
+type ContextConfig struct {
+
+	// Labels are matched by the filter and mirrored onto the cluster namespace like cloud tags
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Region is used by the namespace layout
+	Region string `json:"region,omitempty" yaml:"region,omitempty"`
+
+	// Network is used by the namespace layout
+	Network string `json:"network,omitempty" yaml:"network,omitempty"`
+
+	// NodeCidrBlocks are added to the Kube Nodes and Kube Node Subnets external networks
+	NodeCidrBlocks []string `json:"nodeCidrBlocks,omitempty" yaml:"nodeCidrBlocks,omitempty"`
+
+	// PodCidrBlocks are added to the Kube Pods external network
+	PodCidrBlocks []string `json:"podCidrBlocks,omitempty" yaml:"podCidrBlocks,omitempty"`
+
+	// ServiceCidrBlocks are added to the Kube Services external network
+	ServiceCidrBlocks []string `json:"serviceCidrBlocks,omitempty" yaml:"serviceCidrBlocks,omitempty"`
+
+	// CidrBlocks are the networks of the cluster published as the cluster networks external
+	// network (op KUBE_API_NET)
+	CidrBlocks []string `json:"cidrBlocks,omitempty" yaml:"cidrBlocks,omitempty"`
+
+	// EnforcerProfile selects the enforcer DaemonSet (EKS or GKE) that matches the cluster. It
+	// is required for every context if op KUBE_ENFORCER or KUBE_ENFORCER_UNINSTALL is enabled.
+	EnforcerProfile EnforcerProfile `json:"enforcerProfile,omitempty" yaml:"enforcerProfile,omitempty"`
+}
+
+// NewContextConfig returns new instance
+func NewContextConfig() *ContextConfig {
+	return &ContextConfig{}
+}
+
+// AddLabel adds label and returns self
+func (t *ContextConfig) AddLabel(key, value string) *ContextConfig {
+	if t.Labels == nil {
+		t.Labels = make(map[string]string)
+	}
+	t.Labels[key] = value
+	return t
+}
+
+// SetEnforcerProfile sets attribute and returns self
+func (t *ContextConfig) SetEnforcerProfile(v EnforcerProfile) *ContextConfig {
+	t.EnforcerProfile = v
+	return t
+}
+
+// AddCidrBlocks adds attribute(s) and returns self
+func (t *ContextConfig) AddCidrBlocks(v ...string) *ContextConfig {
+	t.CidrBlocks = append(t.CidrBlocks, v...)
+	return t
+}
+
+// SetRegion sets attribute and returns self
+func (t *ContextConfig) SetRegion(v string) *ContextConfig {
+	t.Region = v
+	return t
+}
+
+// SetNetwork sets attribute and returns self
+func (t *ContextConfig) SetNetwork(v string) *ContextConfig {
+	t.Network = v
+	return t
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnforcerProfileFromString(t *testing.T) {
+
+	tests := map[string]struct {
+		s       string
+		want    EnforcerProfile
+		wantErr bool
+	}{
+		"eks":        {s: "EKS", want: EnforcerProfileEKS},
+		"gke":        {s: "GKE", want: EnforcerProfileGKE},
+		"lower case": {s: "eks", want: EnforcerProfileEKS},
+		"empty":      {s: "", want: EnforcerProfileInvalid, wantErr: true},
+		"unknown":    {s: "AKS", want: EnforcerProfileInvalid, wantErr: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			got, err := EnforcerProfileFromString(test.s)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %t", err, test.wantErr)
+			}
+
+			if got != test.want {
+				t.Fatalf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestKubeconfigFromEnv(t *testing.T) {
+
+	separator := string(os.PathListSeparator)
+
+	tests := map[string]struct {
+		env  string
+		want []string
+	}{
+		"unset keeps the config": {
+			want: []string{"config.yaml"},
+		},
+		"single": {
+			env:  filepath.Join("home", "config"),
+			want: []string{filepath.Join("home", "config")},
+		},
+		"list replaces the config": {
+			env:  strings.Join([]string{"a", "", "b"}, separator),
+			want: []string{"a", "b"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+
+			t.Setenv(KubeconfigEnv, test.env)
+
+			config := NewCloudOperatorConfig().AddKubeconfig("config.yaml")
+
+			err := config.SetFromEnv()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := config.GetKubeconfig()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetKubeconfigRequired(t *testing.T) {
+
+	if _, err := NewCloudOperatorConfig().GetKubeconfig(); err == nil {
+		t.Fatalf("expected error without kubeconfig")
+	}
+}